@@ -3,7 +3,6 @@ package account
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/google/uuid"
 	"github.com/ikeboy003/robinstock-go"
@@ -17,8 +16,8 @@ import (
 // This function is maintained for API completeness but may fail with TLS handshake errors.
 // Use GetAllPositions and other specific endpoints for reliable data access.
 func LoadPhoenixAccount(ctx context.Context, client *robinstock_go.Client) (map[string]interface{}, error) {
-	log.Println("LoadPhoenixAccount: Fetching Phoenix account data...")
-	log.Println("LoadPhoenixAccount: WARNING - Phoenix endpoint has known TLS issues")
+	client.Logger().DebugContext(ctx, "fetching Phoenix account data", "op", "LoadPhoenixAccount")
+	client.Logger().WarnContext(ctx, "phoenix endpoint has known TLS issues", "op", "LoadPhoenixAccount")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -26,7 +25,7 @@ func LoadPhoenixAccount(ctx context.Context, client *robinstock_go.Client) (map[
 
 	resp, err := client.Get(ctx, urls.PhoenixURL(), nil, true)
 	if err != nil {
-		log.Printf("LoadPhoenixAccount: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "LoadPhoenixAccount", "error", err)
 		return nil, err
 	}
 
@@ -35,50 +34,34 @@ func LoadPhoenixAccount(ctx context.Context, client *robinstock_go.Client) (map[
 
 // GetAllPositions returns all positions.
 func GetAllPositions(ctx context.Context, client *robinstock_go.Client) ([]models.Position, error) {
-	log.Println("GetAllPositions: Fetching all positions...")
+	client.Logger().DebugContext(ctx, "fetching all positions", "op", "GetAllPositions")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	results, err := client.FetchAllPages(ctx, urls.PositionsURL(), true)
+	positions, err := robinstock_go.FetchAllPagesTyped[models.Position](ctx, client, urls.PositionsURL(), true)
 	if err != nil {
-		log.Printf("GetAllPositions: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetAllPositions", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetAllPositions: Received %d positions\n", len(results))
-
-	var positions []models.Position
-	for _, result := range results {
-		position := models.Position{
-			URL:                      utils.GetString(result, "url"),
-			Instrument:               utils.GetString(result, "instrument"),
-			InstrumentID:             utils.GetString(result, "instrument_id"),
-			Account:                  utils.GetString(result, "account"),
-			AccountNumber:            utils.GetString(result, "account_number"),
-			AverageBuyPrice:          utils.GetString(result, "average_buy_price"),
-			PendingAverageBuyPrice:   utils.GetString(result, "pending_average_buy_price"),
-			Quantity:                 utils.GetString(result, "quantity"),
-			IntradayAverageBuyPrice:  utils.GetString(result, "intraday_average_buy_price"),
-			IntradayQuantity:         utils.GetString(result, "intraday_quantity"),
-			SharesHeldForBuys:        utils.GetString(result, "shares_held_for_buys"),
-			SharesHeldForSells:       utils.GetString(result, "shares_held_for_sells"),
-			UpdatedAt:                utils.GetString(result, "updated_at"),
-			CreatedAt:                utils.GetString(result, "created_at"),
-		}
-		positions = append(positions, position)
-	}
-
+	client.Logger().DebugContext(ctx, "received positions", "op", "GetAllPositions", "count", len(positions))
 	return positions, nil
 }
 
+// PositionPages returns a Pager over the user's stock positions, fetching
+// pages only as they are consumed.
+func PositionPages(client *robinstock_go.Client, opts ...robinstock_go.PagerOption) *robinstock_go.Pager {
+	return client.Pages(urls.PositionsURL(), true, opts...)
+}
+
 // GetOpenStockPosition returns open positions, optionally filtered by account number.
 func GetOpenStockPosition(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]models.Position, error) {
 	if accountNumber != nil {
-		log.Printf("GetOpenStockPosition: Fetching for account %s...\n", *accountNumber)
+		client.Logger().DebugContext(ctx, "fetching open positions", "op", "GetOpenStockPosition", "account_number", *accountNumber)
 	} else {
-		log.Println("GetOpenStockPosition: Fetching all open positions...")
+		client.Logger().DebugContext(ctx, "fetching all open positions", "op", "GetOpenStockPosition")
 	}
 
 	if !client.IsAuthenticated() {
@@ -86,117 +69,60 @@ func GetOpenStockPosition(ctx context.Context, client *robinstock_go.Client, acc
 	}
 
 	params := map[string]string{"nonzero": "true"}
-	url := urls.PositionsURL()
 	if accountNumber != nil {
-		url = utils.BuildURL(url, map[string]string{"account_number": *accountNumber})
+		params["account_number"] = *accountNumber
 	}
+	url := utils.BuildURL(urls.PositionsURL(), params)
 
-	results, err := client.FetchAllPages(ctx, url, true)
+	positions, err := robinstock_go.FetchAllPagesTyped[models.Position](ctx, client, url, true)
 	if err != nil {
-		log.Printf("GetOpenStockPosition: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetOpenStockPosition", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetOpenStockPosition: Received %d positions\n", len(results))
-
-	var positions []models.Position
-	for _, result := range results {
-		position := models.Position{
-			URL:                      utils.GetString(result, "url"),
-			Instrument:               utils.GetString(result, "instrument"),
-			InstrumentID:             utils.GetString(result, "instrument_id"),
-			Account:                  utils.GetString(result, "account"),
-			AccountNumber:            utils.GetString(result, "account_number"),
-			AverageBuyPrice:          utils.GetString(result, "average_buy_price"),
-			PendingAverageBuyPrice:   utils.GetString(result, "pending_average_buy_price"),
-			Quantity:                 utils.GetString(result, "quantity"),
-			IntradayAverageBuyPrice:  utils.GetString(result, "intraday_average_buy_price"),
-			IntradayQuantity:         utils.GetString(result, "intraday_quantity"),
-			SharesHeldForBuys:        utils.GetString(result, "shares_held_for_buys"),
-			SharesHeldForSells:       utils.GetString(result, "shares_held_for_sells"),
-			UpdatedAt:                utils.GetString(result, "updated_at"),
-			CreatedAt:                utils.GetString(result, "created_at"),
-		}
-		positions = append(positions, position)
-	}
-
-	_ = params
+	client.Logger().DebugContext(ctx, "received positions", "op", "GetOpenStockPosition", "count", len(positions))
 	return positions, nil
 }
 
 // GetDividends returns dividend history.
 func GetDividends(ctx context.Context, client *robinstock_go.Client) ([]models.Dividend, error) {
-	log.Println("GetDividends: Fetching dividend history...")
+	client.Logger().DebugContext(ctx, "fetching dividend history", "op", "GetDividends")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	results, err := client.FetchAllPages(ctx, urls.DividendsURL(), true)
+	dividends, err := robinstock_go.FetchAllPagesTyped[models.Dividend](ctx, client, urls.DividendsURL(), true)
 	if err != nil {
-		log.Printf("GetDividends: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetDividends", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetDividends: Received %d dividends\n", len(results))
-
-	var dividends []models.Dividend
-	for _, result := range results {
-		dividend := models.Dividend{
-			ID:                utils.GetString(result, "id"),
-			URL:               utils.GetString(result, "url"),
-			Account:           utils.GetString(result, "account"),
-			Instrument:        utils.GetString(result, "instrument"),
-			Amount:            utils.GetString(result, "amount"),
-			Rate:              utils.GetString(result, "rate"),
-			Position:          utils.GetString(result, "position"),
-			WithholdingAmount: utils.GetString(result, "withholding"),
-			RecordDate:        utils.GetString(result, "record_date"),
-			PayableDate:       utils.GetString(result, "payable_date"),
-			PaidAt:            utils.GetString(result, "paid_at"),
-			State:             utils.GetString(result, "state"),
-		}
-		dividends = append(dividends, dividend)
-	}
-
+	client.Logger().DebugContext(ctx, "received dividends", "op", "GetDividends", "count", len(dividends))
 	return dividends, nil
 }
 
 // GetNotifications returns account notifications.
 func GetNotifications(ctx context.Context, client *robinstock_go.Client) ([]models.Notification, error) {
-	log.Println("GetNotifications: Fetching notifications...")
+	client.Logger().DebugContext(ctx, "fetching notifications", "op", "GetNotifications")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	results, err := client.FetchAllPages(ctx, urls.NotificationsURL(false), true)
+	notifications, err := robinstock_go.FetchAllPagesTyped[models.Notification](ctx, client, urls.NotificationsURL(false), true)
 	if err != nil {
-		log.Printf("GetNotifications: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetNotifications", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetNotifications: Received %d notifications\n", len(results))
-
-	var notifications []models.Notification
-	for _, result := range results {
-		notification := models.Notification{
-			ID:         utils.GetString(result, "id"),
-			URL:        utils.GetString(result, "url"),
-			Type:       utils.GetString(result, "type"),
-			ARN:        utils.GetString(result, "arn"),
-			Identifier: utils.GetString(result, "identifier"),
-			Token:      utils.GetString(result, "token"),
-		}
-		notifications = append(notifications, notification)
-	}
-
+	client.Logger().DebugContext(ctx, "received notifications", "op", "GetNotifications", "count", len(notifications))
 	return notifications, nil
 }
 
 // GetLinkedBankAccounts returns all linked bank accounts.
 func GetLinkedBankAccounts(ctx context.Context, client *robinstock_go.Client) ([]map[string]interface{}, error) {
-	log.Println("GetLinkedBankAccounts: Fetching linked bank accounts...")
+	client.Logger().DebugContext(ctx, "fetching linked bank accounts", "op", "GetLinkedBankAccounts")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -204,17 +130,17 @@ func GetLinkedBankAccounts(ctx context.Context, client *robinstock_go.Client) ([
 
 	results, err := client.FetchAllPages(ctx, urls.LinkedURL(), true)
 	if err != nil {
-		log.Printf("GetLinkedBankAccounts: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetLinkedBankAccounts", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetLinkedBankAccounts: Received %d accounts\n", len(results))
+	client.Logger().DebugContext(ctx, "received accounts", "op", "GetLinkedBankAccounts", "count", len(results))
 	return results, nil
 }
 
 // DepositFundsIntoRobinhood deposits funds from a linked bank account.
 func DepositFundsIntoRobinhood(ctx context.Context, client *robinstock_go.Client, accountNumber string, amount float64) (map[string]interface{}, error) {
-	log.Printf("DepositFundsIntoRobinhood: Depositing $%.2f from account %s...\n", amount, accountNumber)
+	client.Logger().InfoContext(ctx, "initiating deposit", "op", "DepositFundsIntoRobinhood", "amount", amount, "bank_account_number", accountNumber)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -234,17 +160,17 @@ func DepositFundsIntoRobinhood(ctx context.Context, client *robinstock_go.Client
 
 	resp, err := client.Post(ctx, urls.BankTransfersURL(), payload, true)
 	if err != nil {
-		log.Printf("DepositFundsIntoRobinhood: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "DepositFundsIntoRobinhood", "error", err)
 		return nil, err
 	}
 
-	log.Println("DepositFundsIntoRobinhood: Deposit initiated successfully")
+	client.Logger().InfoContext(ctx, "deposit initiated", "op", "DepositFundsIntoRobinhood")
 	return resp.Data, nil
 }
 
 // WithdrawFundsFromRobinhood withdraws funds to a linked bank account.
 func WithdrawFundsFromRobinhood(ctx context.Context, client *robinstock_go.Client, accountNumber string, amount float64) (map[string]interface{}, error) {
-	log.Printf("WithdrawFundsFromRobinhood: Withdrawing $%.2f to account %s...\n", amount, accountNumber)
+	client.Logger().InfoContext(ctx, "initiating withdrawal", "op", "WithdrawFundsFromRobinhood", "amount", amount, "bank_account_number", accountNumber)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -264,11 +190,11 @@ func WithdrawFundsFromRobinhood(ctx context.Context, client *robinstock_go.Clien
 
 	resp, err := client.Post(ctx, urls.BankTransfersURL(), payload, true)
 	if err != nil {
-		log.Printf("WithdrawFundsFromRobinhood: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "WithdrawFundsFromRobinhood", "error", err)
 		return nil, err
 	}
 
-	log.Println("WithdrawFundsFromRobinhood: Withdrawal initiated successfully")
+	client.Logger().InfoContext(ctx, "withdrawal initiated", "op", "WithdrawFundsFromRobinhood")
 	return resp.Data, nil
 }
 
@@ -293,7 +219,7 @@ func getBankURLByAccountNumber(ctx context.Context, client *robinstock_go.Client
 
 // BuildHoldings builds detailed holdings data with calculations.
 func BuildHoldings(ctx context.Context, client *robinstock_go.Client, withDividend bool) ([]models.Holding, error) {
-	log.Println("BuildHoldings: Building holdings data...")
+	client.Logger().DebugContext(ctx, "building holdings data", "op", "BuildHoldings")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -345,8 +271,7 @@ func BuildHoldings(ctx context.Context, client *robinstock_go.Client, withDivide
 		holdings = append(holdings, holding)
 	}
 
-	log.Printf("BuildHoldings: Built %d holdings\n", len(holdings))
-	log.Printf("BuildHoldings: Total equity: %.2f, Cash: %.2f\n", totalEquity, cash)
+	client.Logger().DebugContext(ctx, "built holdings", "op", "BuildHoldings", "count", len(holdings), "total_equity", totalEquity, "cash", cash)
 
 	_ = portfolio
 	_ = withDividend
@@ -356,42 +281,29 @@ func BuildHoldings(ctx context.Context, client *robinstock_go.Client, withDivide
 
 // GetPortfolio returns portfolio data for an account.
 func GetPortfolio(ctx context.Context, client *robinstock_go.Client, accountNumber *string) (*models.Portfolio, error) {
-	log.Println("GetPortfolio: Fetching portfolio...")
+	client.Logger().DebugContext(ctx, "fetching portfolio", "op", "GetPortfolio")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	url := urls.PortfoliosURL()
 	if accountNumber != nil {
-		url = urls.PortfolioURL(*accountNumber)
+		portfolio, err := robinstock_go.GetJSON[models.Portfolio](ctx, client, urls.PortfolioURL(*accountNumber), nil, true)
+		if err != nil {
+			client.Logger().ErrorContext(ctx, "request failed", "op", "GetPortfolio", "error", err)
+			return nil, err
+		}
+		return portfolio, nil
 	}
 
-	resp, err := client.Get(ctx, url, nil, true)
+	page, err := robinstock_go.GetJSON[robinstock_go.Page[models.Portfolio]](ctx, client, urls.PortfoliosURL(), nil, true)
 	if err != nil {
-		log.Printf("GetPortfolio: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetPortfolio", "error", err)
 		return nil, err
 	}
-
-	data := resp.Data
-	if len(resp.Results) > 0 {
-		data = resp.Results[0]
-	}
-
-	portfolio := &models.Portfolio{
-		URL:                          utils.GetString(data, "url"),
-		Account:                      utils.GetString(data, "account"),
-		StartDate:                    utils.GetString(data, "start_date"),
-		MarketValue:                  utils.GetString(data, "market_value"),
-		Equity:                       utils.GetString(data, "equity"),
-		ExtendedHoursMarketValue:     utils.GetString(data, "extended_hours_market_value"),
-		ExtendedHoursEquity:          utils.GetString(data, "extended_hours_equity"),
-		ExcessMargin:                 utils.GetString(data, "excess_margin"),
-		EquityPreviousClose:          utils.GetString(data, "equity_previous_close"),
-		AdjustedEquityPreviousClose:  utils.GetString(data, "adjusted_equity_previous_close"),
-		Withdrawable:                 utils.GetString(data, "withdrawable"),
-		UnsettledFunds:               utils.GetString(data, "unsettled_funds"),
+	if len(page.Results) == 0 {
+		return nil, fmt.Errorf("no portfolios found")
 	}
 
-	return portfolio, nil
+	return &page.Results[0], nil
 }
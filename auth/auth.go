@@ -4,17 +4,25 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
+	"time"
 
 	"github.com/ikeboy003/robinstock-go"
 	"github.com/ikeboy003/robinstock-go/models"
 	"github.com/ikeboy003/robinstock-go/urls"
 )
 
-// Login authenticates with Robinhood and returns auth credentials.
-func Login(ctx context.Context, client *robinstock_go.Client, username, password, mfaCode string) (*models.Auth, error) {
-	if token, ok := tryLoadStoredToken(username); ok {
+// Login authenticates with Robinhood and returns auth credentials. An
+// unexpired token saved by a previous Login is reused. Failing to save the
+// new token, e.g. on a read-only filesystem, is logged at warn level and
+// does not fail the login. Without a home directory for the default file
+// store, tokens are not persisted at all.
+func Login(ctx context.Context, client *robinstock_go.Client, username, password, mfaCode string, opts ...LoginOption) (*models.Auth, error) {
+	cfg := newLoginConfig(ctx, client, opts)
+
+	if token, ok := loadStoredToken(ctx, client, cfg.store, username); ok {
 		client.SetAuth(token)
 		return token, nil
 	}
@@ -24,6 +32,16 @@ func Login(ctx context.Context, client *robinstock_go.Client, username, password
 		return nil, fmt.Errorf("generate device token: %w", err)
 	}
 
+	usingTOTP := mfaCode == "" && cfg.totpSecret != ""
+	var codeIssued time.Time
+	if usingTOTP {
+		codeIssued = cfg.now()
+		mfaCode, err = GenerateTOTP(cfg.totpSecret, codeIssued)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	payload := map[string]string{
 		"username":       username,
 		"password":       password,
@@ -36,25 +54,44 @@ func Login(ctx context.Context, client *robinstock_go.Client, username, password
 		"challenge_type": "email",
 	}
 
-	resp, err := client.Post(ctx, urls.LoginURL(), payload, false)
-	if err != nil {
-		return nil, err
+	resp, loginErr := postLogin(ctx, client, payload, nil)
+	if resp == nil {
+		return nil, loginErr
 	}
 
 	// Check for Sheriff verification workflow FIRST (can come with 403 status)
 	if verificationWorkflow, ok := resp.Data["verification_workflow"].(map[string]interface{}); ok {
 		workflowID := robinstock_go.GetString(verificationWorkflow, "id")
 		if workflowID != "" {
-			log.Println("Sheriff verification required, starting workflow...")
-			if err := handleSheriffVerification(ctx, client, deviceToken, workflowID); err != nil {
+			client.Logger().InfoContext(ctx, "sheriff verification required", "workflow_id", workflowID)
+			if err := handleSheriffVerification(ctx, client, deviceToken, workflowID, cfg.sheriff, cfg.verificationHandler); err != nil {
 				return nil, fmt.Errorf("sheriff verification failed: %w", err)
 			}
 
-			log.Println("Retrying login after Sheriff verification...")
-			resp, err = client.Post(ctx, urls.LoginURL(), payload, false)
-			if err != nil {
-				return nil, fmt.Errorf("login after verification failed: %w", err)
+			client.Logger().InfoContext(ctx, "retrying login after sheriff verification")
+			resp, loginErr = postLogin(ctx, client, payload, nil)
+			if resp == nil {
+				return nil, fmt.Errorf("login after verification failed: %w", loginErr)
+			}
+		}
+	}
+
+	// A generated code may have been issued just before its window closed
+	if usingTOTP && mfaRejected(resp, loginErr) && nearStepEdge(codeIssued, cfg.now()) {
+		client.Logger().InfoContext(ctx, "TOTP code rejected at a step boundary, retrying with next time step")
+		payload["mfa_code"], err = GenerateTOTP(cfg.totpSecret, stepEnd(codeIssued))
+		if err != nil {
+			return nil, err
+		}
+		resp, loginErr = postLogin(ctx, client, payload, nil)
+		if resp == nil {
+			return nil, fmt.Errorf("login with next TOTP code failed: %w", loginErr)
+		}
+		if mfaRejected(resp, loginErr) {
+			if loginErr != nil {
+				return nil, fmt.Errorf("MFA code rejected: %w", loginErr)
 			}
+			return nil, fmt.Errorf("MFA code rejected")
 		}
 	}
 
@@ -64,17 +101,25 @@ func Login(ctx context.Context, client *robinstock_go.Client, username, password
 	}
 
 	// Check for challenge
-	if challenge, ok := resp.Data["challenge"].(map[string]interface{}); ok {
-		challengeID, _ := challenge["id"].(string)
-		return nil, fmt.Errorf("challenge required: %s", challengeID)
+	if data, ok := resp.Data["challenge"].(map[string]interface{}); ok {
+		challenge := parseChallenge(data)
+		if cfg.challengeHandler == nil {
+			return nil, fmt.Errorf("challenge required: %s", challenge.ID)
+		}
+		if err := respondToChallenge(ctx, client, cfg.challengeHandler, challenge); err != nil {
+			return nil, fmt.Errorf("challenge failed: %w", err)
+		}
+
+		client.Logger().InfoContext(ctx, "retrying login after challenge", "challenge_id", challenge.ID)
+		resp, loginErr = postLogin(ctx, client, payload, challengeHeader(challenge.ID))
+		if resp == nil {
+			return nil, fmt.Errorf("login after challenge failed: %w", loginErr)
+		}
 	}
 
 	// Now check for error status codes
-	if resp.StatusCode >= 400 {
-		if detail, ok := resp.Data["detail"].(string); ok {
-			return nil, fmt.Errorf("login failed: %s", detail)
-		}
-		return nil, fmt.Errorf("login failed: status %d", resp.StatusCode)
+	if loginErr != nil {
+		return nil, fmt.Errorf("login failed: %w", loginErr)
 	}
 
 	auth := &models.Auth{
@@ -83,6 +128,7 @@ func Login(ctx context.Context, client *robinstock_go.Client, username, password
 		TokenType:    robinstock_go.GetString(resp.Data, "token_type"),
 		DeviceToken:  deviceToken,
 		ExpiresIn:    robinstock_go.GetInt(resp.Data, "expires_in"),
+		IssuedAt:     time.Now(),
 	}
 
 	if auth.AccessToken == "" {
@@ -91,48 +137,97 @@ func Login(ctx context.Context, client *robinstock_go.Client, username, password
 
 	client.SetAuth(auth)
 
-	if err := saveToken(username, auth); err != nil {
-		return nil, fmt.Errorf("failed to save token: %w", err)
+	if err := cfg.store.Save(ctx, username, auth); err != nil {
+		client.Logger().WarnContext(ctx, "save token failed", "error", err)
 	}
 
 	return auth, nil
 }
 
-// RefreshToken refreshes an existing authentication token.
+// RefreshToken installs refreshToken and deviceToken on the client and
+// refreshes through Client.RefreshAuth, so it is serialized with automatic
+// refreshes and reaches the WithTokenRefreshHandler callback.
 func RefreshToken(ctx context.Context, client *robinstock_go.Client, refreshToken, deviceToken string) (*models.Auth, error) {
-	payload := map[string]string{
-		"client_id":     models.ClientID,
-		"grant_type":    "refresh_token",
-		"refresh_token": refreshToken,
-		"device_token":  deviceToken,
-		"scope":         "internal",
+	auth := &models.Auth{}
+	if current := client.GetAuth(); current != nil {
+		*auth = *current
 	}
+	auth.RefreshToken = refreshToken
+	auth.DeviceToken = deviceToken
+	client.SetAuth(auth)
 
-	resp, err := client.Post(ctx, urls.LoginURL(), payload, false)
+	refreshed, err := client.RefreshAuth(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("refresh failed: %w", err)
 	}
+	return refreshed, nil
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("refresh failed: status %d", resp.StatusCode)
+// postLogin posts to the token endpoint. Robinhood reports verification
+// workflows, MFA and challenges with 4xx statuses, so the decoded body of an
+// *APIError is returned alongside the error for the caller to inspect.
+func postLogin(ctx context.Context, client *robinstock_go.Client, payload map[string]string, header http.Header) (*models.Response, error) {
+	resp, err := client.Do(ctx, &robinstock_go.Request{
+		Method: http.MethodPost,
+		URL:    urls.LoginURL(),
+		Body:   payload,
+		Header: header,
+	})
+	if err != nil {
+		var apiErr *robinstock_go.APIError
+		if errors.As(err, &apiErr) {
+			return apiErr.Response, err
+		}
+		return nil, err
 	}
+	return resp, nil
+}
 
-	auth := &models.Auth{
-		AccessToken:  robinstock_go.GetString(resp.Data, "access_token"),
-		RefreshToken: robinstock_go.GetString(resp.Data, "refresh_token"),
-		TokenType:    robinstock_go.GetString(resp.Data, "token_type"),
-		DeviceToken:  deviceToken,
-		ExpiresIn:    robinstock_go.GetInt(resp.Data, "expires_in"),
+// mfaRejected reports whether a login response asks for, or refuses, the
+// MFA code: it still carries mfa_required, or is a 400 with a validation
+// error on the mfa_code field.
+func mfaRejected(resp *models.Response, loginErr error) bool {
+	if mfaRequired, ok := resp.Data["mfa_required"].(bool); ok && mfaRequired {
+		return true
 	}
-
-	client.SetAuth(auth)
-	return auth, nil
+	var apiErr *robinstock_go.APIError
+	if errors.As(loginErr, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		_, ok := apiErr.FieldErrors["mfa_code"]
+		return ok
+	}
+	return false
 }
 
-// Logout clears authentication and deletes stored token.
-func Logout(username string, client *robinstock_go.Client) {
-	deleteToken(username)
+// Logout clears authentication and deletes the stored token.
+func Logout(username string, client *robinstock_go.Client, opts ...LoginOption) {
 	client.SetAuth(nil)
+
+	ctx := context.Background()
+	cfg := newLoginConfig(ctx, client, opts)
+	if err := cfg.store.Delete(ctx, username); err != nil {
+		client.Logger().Warn("delete stored token failed", "error", err)
+	}
+}
+
+// loadStoredToken returns the token stored for username if it has not
+// expired. Expired tokens are deleted.
+func loadStoredToken(ctx context.Context, client *robinstock_go.Client, store TokenStore, username string) (*models.Auth, bool) {
+	token, err := store.Load(ctx, username)
+	if err != nil {
+		if !errors.Is(err, ErrTokenNotFound) {
+			client.Logger().WarnContext(ctx, "load stored token failed", "error", err)
+		}
+		return nil, false
+	}
+
+	if token.IsExpired() {
+		if err := store.Delete(ctx, username); err != nil {
+			client.Logger().WarnContext(ctx, "delete expired token failed", "error", err)
+		}
+		return nil, false
+	}
+
+	return token, true
 }
 
 // generateDeviceToken generates a unique device identifier.
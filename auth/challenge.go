@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
+	"github.com/ikeboy003/robinstock-go/urls"
+)
+
+const challengeResponseHeader = "X-ROBINHOOD-CHALLENGE-RESPONSE-ID"
+
+// ChallengeHandler is asked for the code Robinhood sent by SMS or email. It
+// is called again with the updated challenge if a code is rejected and
+// attempts remain.
+type ChallengeHandler func(ctx context.Context, challenge models.Challenge) (string, error)
+
+// ChannelChallengeHandler returns a ChallengeHandler that sends each
+// challenge on prompts and waits for the code on codes, for callers that
+// collect the code from another goroutine such as a UI or HTTP handler.
+func ChannelChallengeHandler(prompts chan<- models.Challenge, codes <-chan string) ChallengeHandler {
+	return func(ctx context.Context, challenge models.Challenge) (string, error) {
+		select {
+		case prompts <- challenge:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+
+		select {
+		case code, ok := <-codes:
+			if !ok {
+				return "", errors.New("challenge code channel closed")
+			}
+			return code, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// respondToChallenge collects codes from handler and posts them until
+// Robinhood validates the challenge or no attempts remain.
+func respondToChallenge(ctx context.Context, client *robinstock_go.Client, handler ChallengeHandler, challenge models.Challenge) error {
+	for {
+		code, err := handler(ctx, challenge)
+		if err != nil {
+			return err
+		}
+
+		client.Logger().InfoContext(ctx, "responding to challenge", "challenge_id", challenge.ID, "type", challenge.Type)
+		resp, err := client.Post(ctx, urls.ChallengeURL(challenge.ID), map[string]string{"response": code}, false)
+		if err == nil {
+			if status := robinstock_go.GetString(resp.Data, "status"); status != "validated" {
+				return fmt.Errorf("challenge %s: status %q", challenge.ID, status)
+			}
+			return nil
+		}
+
+		var apiErr *robinstock_go.APIError
+		if !errors.As(err, &apiErr) || apiErr.Response == nil {
+			return err
+		}
+		next, ok := apiErr.Response.Data["challenge"].(map[string]interface{})
+		if !ok {
+			return err
+		}
+		challenge = parseChallenge(next)
+		if challenge.RemainingAttempts <= 0 {
+			return fmt.Errorf("challenge %s: no attempts remaining: %w", challenge.ID, err)
+		}
+		client.Logger().InfoContext(ctx, "challenge code rejected", "challenge_id", challenge.ID, "remaining_attempts", challenge.RemainingAttempts)
+	}
+}
+
+// challengeHeader marks a login retry as answering challengeID.
+func challengeHeader(challengeID string) http.Header {
+	header := make(http.Header)
+	header.Set(challengeResponseHeader, challengeID)
+	return header
+}
+
+func parseChallenge(data map[string]interface{}) models.Challenge {
+	return models.Challenge{
+		ID:                robinstock_go.GetString(data, "id"),
+		Type:              robinstock_go.GetString(data, "type"),
+		Status:            robinstock_go.GetString(data, "status"),
+		RemainingAttempts: robinstock_go.GetInt(data, "remaining_attempts"),
+		RemainingRetries:  robinstock_go.GetInt(data, "remaining_retries"),
+		ExpiresAt:         robinstock_go.GetString(data, "expires_at"),
+	}
+}
@@ -0,0 +1,309 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ikeboy003/robinstock-go/models"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	encryptedFilePrefix = "robinhood_"
+	encryptedFileSuffix = ".enc"
+
+	kdfScrypt = "scrypt"
+	kdfNone   = "none"
+
+	keySize  = 32
+	saltSize = 16
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrDecrypt is returned when a stored token cannot be decrypted, usually
+// because the passphrase or key is wrong.
+var ErrDecrypt = errors.New("decrypt token: wrong key or corrupted file")
+
+// EncryptedFileStore stores each token as an AES-256-GCM encrypted file in a
+// directory. The key is either derived with scrypt from a passphrase, using
+// a fresh salt per file, or read from a key file. The token's key is bound
+// to its ciphertext, so files cannot be swapped between users.
+type EncryptedFileStore struct {
+	dir        string
+	passphrase []byte
+	key        []byte
+}
+
+// encryptedFile is the on-disk format of an EncryptedFileStore token.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewEncryptedFileStore returns an EncryptedFileStore in dir whose key is
+// derived from passphrase.
+func NewEncryptedFileStore(dir string, passphrase []byte) (*EncryptedFileStore, error) {
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+	return &EncryptedFileStore{dir: dir, passphrase: bytes.Clone(passphrase)}, nil
+}
+
+// NewEncryptedFileStoreWithKeyFile returns an EncryptedFileStore in dir that
+// uses the 32-byte key in keyFile, stored raw or base64 encoded.
+func NewEncryptedFileStoreWithKeyFile(dir, keyFile string) (*EncryptedFileStore, error) {
+	content, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+
+	key := content
+	if len(key) != keySize {
+		key, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("key file must hold a %d-byte key", keySize)
+		}
+	}
+	return &EncryptedFileStore{dir: dir, key: key}, nil
+}
+
+// GenerateKeyFile writes a random base64 encoded key to path with mode 0600.
+// It refuses to overwrite an existing file.
+func GenerateKeyFile(path string) error {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load decrypts the token stored under key.
+func (s *EncryptedFileStore) Load(ctx context.Context, key string) (*models.Auth, error) {
+	content, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := s.decrypt(key, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var token models.Auth
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return nil, fmt.Errorf("decode token: %w", err)
+	}
+	return &token, nil
+}
+
+// Save encrypts auth and writes it under key with mode 0600.
+func (s *EncryptedFileStore) Save(ctx context.Context, key string, auth *models.Auth) error {
+	plaintext, err := json.Marshal(auth)
+	if err != nil {
+		return err
+	}
+
+	content, err := s.encrypt(key, plaintext)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path(key), content)
+}
+
+// Delete removes the token stored under key. Deleting a missing token is
+// not an error.
+func (s *EncryptedFileStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Rotate re-encrypts every token in the store for next, which may use a
+// different passphrase, key file or directory. All tokens are decrypted
+// before anything is written, so a wrong current key changes nothing. When
+// next uses the same directory the files are replaced in place.
+func (s *EncryptedFileStore) Rotate(ctx context.Context, next *EncryptedFileStore) error {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tokens := make(map[string][]byte)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, encryptedFilePrefix) || !strings.HasSuffix(name, encryptedFileSuffix) {
+			continue
+		}
+		key, err := url.PathUnescape(strings.TrimSuffix(strings.TrimPrefix(name, encryptedFilePrefix), encryptedFileSuffix))
+		if err != nil {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return err
+		}
+		plaintext, err := s.decrypt(key, content)
+		if err != nil {
+			return fmt.Errorf("rotate %s: %w", key, err)
+		}
+		tokens[key] = plaintext
+	}
+
+	for key, plaintext := range tokens {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		content, err := next.encrypt(key, plaintext)
+		if err != nil {
+			return fmt.Errorf("rotate %s: %w", key, err)
+		}
+		if err := writeFileAtomic(next.path(key), content); err != nil {
+			return fmt.Errorf("rotate %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (s *EncryptedFileStore) path(key string) string {
+	return filepath.Join(s.dir, encryptedFilePrefix+url.PathEscape(key)+encryptedFileSuffix)
+}
+
+func (s *EncryptedFileStore) encrypt(key string, plaintext []byte) ([]byte, error) {
+	file := encryptedFile{Version: 1, KDF: kdfNone}
+	if s.key == nil {
+		file.KDF = kdfScrypt
+		file.Salt = make([]byte, saltSize)
+		if _, err := rand.Read(file.Salt); err != nil {
+			return nil, err
+		}
+	}
+
+	aead, err := s.aead(file.KDF, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return nil, err
+	}
+	file.Ciphertext = aead.Seal(nil, file.Nonce, plaintext, []byte(key))
+
+	return json.Marshal(file)
+}
+
+func (s *EncryptedFileStore) decrypt(key string, content []byte) ([]byte, error) {
+	var file encryptedFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("decode encrypted token: %w", err)
+	}
+	if file.Version != 1 {
+		return nil, fmt.Errorf("unsupported encrypted token version %d", file.Version)
+	}
+
+	aead, err := s.aead(file.KDF, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, []byte(key))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// aead returns the cipher for a file encrypted with kdf and salt.
+func (s *EncryptedFileStore) aead(kdf string, salt []byte) (cipher.AEAD, error) {
+	var key []byte
+	switch kdf {
+	case kdfScrypt:
+		if s.passphrase == nil {
+			return nil, fmt.Errorf("token was encrypted with a passphrase but the store uses a key file")
+		}
+		var err error
+		key, err = scrypt.Key(s.passphrase, salt, scryptN, scryptR, scryptP, keySize)
+		if err != nil {
+			return nil, err
+		}
+	case kdfNone:
+		if s.key == nil {
+			return nil, fmt.Errorf("token was encrypted with a key file but the store uses a passphrase")
+		}
+		key = s.key
+	default:
+		return nil, fmt.Errorf("unsupported key derivation %q", kdf)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never see a partial token.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+var testToken = &models.Auth{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", DeviceToken: "device"}
+
+func passphraseStore(t *testing.T, dir, passphrase string) *EncryptedFileStore {
+	t.Helper()
+	s, err := NewEncryptedFileStore(dir, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func keyFileStore(t *testing.T, dir string) *EncryptedFileStore {
+	t.Helper()
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := GenerateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewEncryptedFileStoreWithKeyFile(dir, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func assertToken(t *testing.T, s TokenStore, key string) {
+	t.Helper()
+	got, err := s.Load(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Load(%q): %v", key, err)
+	}
+	if *got != *testToken {
+		t.Fatalf("Load(%q) = %+v, want %+v", key, got, testToken)
+	}
+}
+
+func TestEncryptedFileStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	stores := map[string]*EncryptedFileStore{
+		"passphrase": passphraseStore(t, t.TempDir(), "correct horse"),
+		"key file":   keyFileStore(t, t.TempDir()),
+	}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Save(ctx, "alice@example.com", testToken); err != nil {
+				t.Fatal(err)
+			}
+			assertToken(t, s, "alice@example.com")
+
+			content, err := os.ReadFile(s.path("alice@example.com"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Contains(content, []byte(testToken.AccessToken)) || bytes.Contains(content, []byte(testToken.RefreshToken)) {
+				t.Fatal("token file holds the token in plaintext")
+			}
+			if info, err := os.Stat(s.path("alice@example.com")); err != nil || info.Mode().Perm() != 0600 {
+				t.Fatalf("token file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+			}
+
+			if err := s.Delete(ctx, "alice@example.com"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Load(ctx, "alice@example.com"); !errors.Is(err, ErrTokenNotFound) {
+				t.Fatalf("Load after Delete: err = %v, want ErrTokenNotFound", err)
+			}
+		})
+	}
+}
+
+func TestEncryptedFileStoreWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	if err := passphraseStore(t, dir, "right").Save(context.Background(), "alice", testToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := passphraseStore(t, dir, "wrong").Load(context.Background(), "alice"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestEncryptedFileStoreWrongKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := keyFileStore(t, dir).Save(context.Background(), "alice", testToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keyFileStore(t, dir).Load(context.Background(), "alice"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("err = %v, want ErrDecrypt", err)
+	}
+}
+
+// TestEncryptedFileStoreKeyBound moves alice's token file to bob's name. The
+// key is authenticated data, so the file cannot be read as bob's token.
+func TestEncryptedFileStoreKeyBound(t *testing.T) {
+	s := keyFileStore(t, t.TempDir())
+	if err := s.Save(context.Background(), "alice", testToken); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(s.path("alice"), s.path("bob")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load(context.Background(), "bob"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestEncryptedFileStoreKDFMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	if err := passphraseStore(t, dir, "secret").Save(ctx, "alice", testToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keyFileStore(t, dir).Load(ctx, "alice"); err == nil {
+		t.Fatal("key file store read a passphrase-encrypted token")
+	}
+
+	dir = t.TempDir()
+	if err := keyFileStore(t, dir).Save(ctx, "alice", testToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := passphraseStore(t, dir, "secret").Load(ctx, "alice"); err == nil {
+		t.Fatal("passphrase store read a key-file-encrypted token")
+	}
+}
+
+func TestEncryptedFileStoreKeyFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	raw := filepath.Join(dir, "raw")
+	if err := os.WriteFile(raw, bytes.Repeat([]byte{7}, keySize), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEncryptedFileStoreWithKeyFile(dir, raw); err != nil {
+		t.Fatalf("raw key: %v", err)
+	}
+
+	short := filepath.Join(dir, "short")
+	if err := os.WriteFile(short, []byte("c2hvcnQ=\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEncryptedFileStoreWithKeyFile(dir, short); err == nil {
+		t.Fatal("accepted a short key")
+	}
+
+	generated := filepath.Join(dir, "generated")
+	if err := GenerateKeyFile(generated); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateKeyFile(generated); err == nil {
+		t.Fatal("GenerateKeyFile overwrote an existing key")
+	}
+}
+
+func TestEncryptedFileStoreRotate(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	old := passphraseStore(t, dir, "old")
+	for _, key := range []string{"alice", "bob"} {
+		if err := old.Save(ctx, key, testToken); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	next := keyFileStore(t, dir)
+	if err := old.Rotate(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"alice", "bob"} {
+		assertToken(t, next, key)
+		if _, err := old.Load(ctx, key); err == nil {
+			t.Fatalf("old store still reads %s after rotation", key)
+		}
+	}
+
+	moved := passphraseStore(t, t.TempDir(), "new")
+	if err := next.Rotate(ctx, moved); err != nil {
+		t.Fatal(err)
+	}
+	assertToken(t, moved, "alice")
+	assertToken(t, next, "alice")
+}
+
+func TestEncryptedFileStoreRotateWrongKey(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	current := passphraseStore(t, dir, "current")
+	for _, key := range []string{"alice", "bob"} {
+		if err := current.Save(ctx, key, testToken); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := map[string][]byte{}
+	for _, key := range []string{"alice", "bob"} {
+		content, err := os.ReadFile(current.path(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		before[key] = content
+	}
+
+	wrong := passphraseStore(t, dir, "wrong")
+	if err := wrong.Rotate(ctx, keyFileStore(t, dir)); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("err = %v, want ErrDecrypt", err)
+	}
+	for key, content := range before {
+		after, err := os.ReadFile(current.path(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(after, content) {
+			t.Fatalf("%s changed after a failed rotation", key)
+		}
+		assertToken(t, current, key)
+	}
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go"
+)
+
+// LoginOption configures Login and Logout.
+type LoginOption func(*loginConfig)
+
+type loginConfig struct {
+	store      TokenStore
+	totpSecret string
+
+	challengeHandler ChallengeHandler
+
+	sheriff             SheriffConfig
+	verificationHandler VerificationHandler
+
+	// now is the clock for TOTP codes.
+	now func() time.Time
+}
+
+// WithTokenStore sets where credentials are loaded from and saved to. The
+// default is DefaultFileStore, or NoopStore when the home directory cannot
+// be found; pass NoopStore{} to keep tokens off disk.
+func WithTokenStore(store TokenStore) LoginOption {
+	return func(cfg *loginConfig) {
+		cfg.store = store
+	}
+}
+
+// WithTOTPSecret makes Login generate MFA codes from a base32 TOTP secret,
+// the one shown when enrolling an authenticator app, whenever no mfaCode is
+// passed. If Robinhood rejects a code generated in the last few seconds of
+// its 30-second step, or after that step has ended, Login retries once with
+// the next step's code; other rejections fail the login.
+func WithTOTPSecret(secret string) LoginOption {
+	return func(cfg *loginConfig) {
+		cfg.totpSecret = secret
+	}
+}
+
+// WithChallengeHandler lets Login complete SMS and email challenges by
+// asking handler for the code. Without it Login fails with
+// "challenge required".
+func WithChallengeHandler(handler ChallengeHandler) LoginOption {
+	return func(cfg *loginConfig) {
+		cfg.challengeHandler = handler
+	}
+}
+
+// WithSheriffConfig overrides the timeouts, poll intervals and clock used
+// while waiting for Sheriff device approval.
+func WithSheriffConfig(sheriff SheriffConfig) LoginOption {
+	return func(cfg *loginConfig) {
+		cfg.sheriff = sheriff
+	}
+}
+
+// WithVerificationHandler reports Sheriff device approval progress to
+// handler's hooks.
+func WithVerificationHandler(handler VerificationHandler) LoginOption {
+	return func(cfg *loginConfig) {
+		cfg.verificationHandler = handler
+	}
+}
+
+// newLoginConfig applies opts. Without a store it uses DefaultFileStore,
+// falling back to NoopStore when there is no home directory, as in many
+// containers, so Login still works there without persisting tokens.
+func newLoginConfig(ctx context.Context, client *robinstock_go.Client, opts []LoginOption) *loginConfig {
+	cfg := &loginConfig{now: time.Now}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.sheriff = cfg.sheriff.withDefaults()
+	if cfg.store == nil {
+		store, err := DefaultFileStore()
+		if err != nil {
+			client.Logger().WarnContext(ctx, "default token store unavailable, tokens will not be saved", "error", err)
+			cfg.store = NoopStore{}
+		} else {
+			cfg.store = store
+		}
+	}
+	return cfg
+}
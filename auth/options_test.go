@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/ikeboy003/robinstock-go"
+)
+
+func TestLoginConfigWithoutHome(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("home directory is not read from $HOME")
+	}
+	t.Setenv("HOME", "")
+
+	cfg := newLoginConfig(context.Background(), robinstock_go.NewClient(), nil)
+	if _, ok := cfg.store.(NoopStore); !ok {
+		t.Fatalf("store = %T, want NoopStore when there is no home directory", cfg.store)
+	}
+}
+
+func TestLoginConfigDefaultStore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := newLoginConfig(context.Background(), robinstock_go.NewClient(), nil)
+	if _, ok := cfg.store.(*FileStore); !ok {
+		t.Fatalf("store = %T, want *FileStore", cfg.store)
+	}
+}
@@ -2,16 +2,132 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/ikeboy003/robinstock-go"
 	"github.com/ikeboy003/robinstock-go/urls"
 )
 
-func handleSheriffVerification(ctx context.Context, client *robinstock_go.Client, deviceToken, workflowID string) error {
-	log.Println("Starting Sheriff verification workflow...")
+// ErrVerificationTimeout is returned when Sheriff verification does not
+// finish within SheriffConfig.InquiryTimeout or SheriffConfig.Timeout.
+var ErrVerificationTimeout = errors.New("sheriff verification timeout")
+
+// Clock is the source of time for Sheriff verification. Tests substitute a
+// fake to drive the polling loop without waiting.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SheriffConfig controls the timing of Sheriff device verification. Zero
+// fields take their default values.
+type SheriffConfig struct {
+	// InquiryTimeout bounds the wait for the verification inquiry to
+	// become available. Default 20s.
+	InquiryTimeout time.Duration
+	// InquiryInterval is the delay between inquiry polls. Default 4s.
+	InquiryInterval time.Duration
+	// RetryInterval is the delay after a failed status poll. Default 5s.
+	RetryInterval time.Duration
+	// PendingInterval is the delay between status polls while the prompt
+	// awaits approval. Default 15s.
+	PendingInterval time.Duration
+	// Timeout bounds the wait for approval once the prompt is issued.
+	// Default 2m.
+	Timeout time.Duration
+	// Clock defaults to the system clock.
+	Clock Clock
+}
+
+// DefaultSheriffConfig returns the timing Sheriff verification uses unless
+// configured otherwise.
+func DefaultSheriffConfig() SheriffConfig {
+	return SheriffConfig{
+		InquiryTimeout:  20 * time.Second,
+		InquiryInterval: 4 * time.Second,
+		RetryInterval:   5 * time.Second,
+		PendingInterval: 15 * time.Second,
+		Timeout:         2 * time.Minute,
+		Clock:           realClock{},
+	}
+}
+
+func (c SheriffConfig) withDefaults() SheriffConfig {
+	d := DefaultSheriffConfig()
+	if c.InquiryTimeout > 0 {
+		d.InquiryTimeout = c.InquiryTimeout
+	}
+	if c.InquiryInterval > 0 {
+		d.InquiryInterval = c.InquiryInterval
+	}
+	if c.RetryInterval > 0 {
+		d.RetryInterval = c.RetryInterval
+	}
+	if c.PendingInterval > 0 {
+		d.PendingInterval = c.PendingInterval
+	}
+	if c.Timeout > 0 {
+		d.Timeout = c.Timeout
+	}
+	if c.Clock != nil {
+		d.Clock = c.Clock
+	}
+	return d
+}
+
+// sleep waits for d on the configured clock or until ctx is done.
+func (c SheriffConfig) sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.Clock.After(d):
+		return nil
+	}
+}
+
+// VerificationEvent describes the progress of a Sheriff verification.
+type VerificationEvent struct {
+	WorkflowID    string
+	MachineID     string
+	ChallengeID   string
+	ChallengeType string
+	// Elapsed is the time since the workflow started.
+	Elapsed time.Duration
+}
+
+// VerificationHandler receives Sheriff verification progress, e.g. to tell
+// the user to approve the login on their phone. Nil hooks are skipped.
+type VerificationHandler struct {
+	OnWorkflowStarted func(ctx context.Context, event VerificationEvent)
+	OnPromptIssued    func(ctx context.Context, event VerificationEvent)
+	OnValidated       func(ctx context.Context, event VerificationEvent)
+	OnApproved        func(ctx context.Context, event VerificationEvent)
+	OnTimedOut        func(ctx context.Context, event VerificationEvent)
+}
+
+func notify(ctx context.Context, hook func(context.Context, VerificationEvent), event VerificationEvent) {
+	if hook != nil {
+		hook(ctx, event)
+	}
+}
+
+func handleSheriffVerification(ctx context.Context, client *robinstock_go.Client, deviceToken, workflowID string, cfg SheriffConfig, handler VerificationHandler) error {
+	client.Logger().InfoContext(ctx, "starting sheriff verification workflow", "workflow_id", workflowID)
+
+	clock := cfg.Clock
+	started := clock.Now()
+	event := VerificationEvent{WorkflowID: workflowID}
+	elapsed := func() VerificationEvent {
+		event.Elapsed = clock.Now().Sub(started)
+		return event
+	}
 
 	machinePayload := map[string]interface{}{
 		"device_id": deviceToken,
@@ -34,25 +150,30 @@ func handleSheriffVerification(ctx context.Context, client *robinstock_go.Client
 	if machineID == "" {
 		return fmt.Errorf("no machine ID in response")
 	}
+	event.MachineID = machineID
+	notify(ctx, handler.OnWorkflowStarted, elapsed())
 
 	inquiryURL := urls.SheriffInquiryURL(machineID)
-	log.Println("Waiting for inquiry data...")
+	client.Logger().DebugContext(ctx, "waiting for sheriff inquiry", "machine_id", machineID)
 
-	inquiryTimeout := time.Now().Add(20 * time.Second)
+	inquiryTimeout := clock.Now().Add(cfg.InquiryTimeout)
 	var inquiryData map[string]interface{}
 
-	for time.Now().Before(inquiryTimeout) {
+	for clock.Now().Before(inquiryTimeout) {
 		resp, err := client.Get(ctx, inquiryURL, nil, false)
 		if err == nil && resp != nil && resp.Data != nil {
 			inquiryData = resp.Data
 			break
 		}
-		log.Println("Failed to get inquiry, retrying...")
-		time.Sleep(4 * time.Second)
+		client.Logger().DebugContext(ctx, "sheriff inquiry not ready, retrying", "error", err)
+		if err := cfg.sleep(ctx, cfg.InquiryInterval); err != nil {
+			return err
+		}
 	}
 
 	if inquiryData == nil {
-		return fmt.Errorf("unable to get inquiry data within timeout")
+		notify(ctx, handler.OnTimedOut, elapsed())
+		return fmt.Errorf("unable to get inquiry data: %w", ErrVerificationTimeout)
 	}
 
 	contextData, ok := inquiryData["context"].(map[string]interface{})
@@ -69,27 +190,32 @@ func handleSheriffVerification(ctx context.Context, client *robinstock_go.Client
 	if challengeID == "" {
 		return fmt.Errorf("no challenge ID")
 	}
+	event.ChallengeID = challengeID
+	event.ChallengeType = robinstock_go.GetString(challenge, "type")
 
 	statusURL := urls.SheriffChallengeStatusURL(challengeID)
-	log.Println("Polling sheriff challenge status...")
+	client.Logger().DebugContext(ctx, "polling sheriff challenge status", "challenge_id", challengeID)
 
-	startTime := time.Now()
-	timeout := 2 * time.Minute
+	deadline := clock.Now().Add(cfg.Timeout)
+	prompted := false
 
-	for time.Since(startTime) < timeout {
+	for clock.Now().Before(deadline) {
 		statusResp, err := client.Get(ctx, statusURL, nil, false)
 		if err != nil || statusResp == nil || statusResp.Data == nil {
-			log.Println("Empty challenge status response, retrying...")
-			time.Sleep(5 * time.Second)
+			client.Logger().DebugContext(ctx, "empty sheriff challenge status, retrying", "error", err)
+			if err := cfg.sleep(ctx, cfg.RetryInterval); err != nil {
+				return err
+			}
 			continue
 		}
 
 		status := robinstock_go.GetString(statusResp.Data, "challenge_status")
-		log.Printf("Current Challenge Status: %s\n", status)
+		client.Logger().DebugContext(ctx, "sheriff challenge status", "status", status)
 
 		switch status {
 		case "validated":
-			log.Println("Sheriff ID validation successful.")
+			client.Logger().InfoContext(ctx, "sheriff challenge validated", "challenge_id", challengeID)
+			notify(ctx, handler.OnValidated, elapsed())
 
 			payload := map[string]interface{}{
 				"sequence": 0,
@@ -108,7 +234,8 @@ func handleSheriffVerification(ctx context.Context, client *robinstock_go.Client
 				if ok {
 					result := robinstock_go.GetString(typeContext, "result")
 					if result == "workflow_status_approved" {
-						log.Println("Final workflow approval successful.")
+						client.Logger().InfoContext(ctx, "sheriff workflow approved", "workflow_id", workflowID)
+						notify(ctx, handler.OnApproved, elapsed())
 						return nil
 					}
 				}
@@ -116,14 +243,20 @@ func handleSheriffVerification(ctx context.Context, client *robinstock_go.Client
 			return fmt.Errorf("workflow approval failed after validation")
 
 		case "issued":
-			log.Println("Challenge still pending, waiting...")
-			time.Sleep(15 * time.Second)
+			client.Logger().InfoContext(ctx, "sheriff challenge pending approval", "challenge_id", challengeID)
+			if !prompted {
+				prompted = true
+				notify(ctx, handler.OnPromptIssued, elapsed())
+			}
+			if err := cfg.sleep(ctx, cfg.PendingInterval); err != nil {
+				return err
+			}
 
 		default:
 			return fmt.Errorf("unexpected challenge status: %s", status)
 		}
 	}
 
-	return fmt.Errorf("sheriff verification timeout")
+	notify(ctx, handler.OnTimedOut, elapsed())
+	return ErrVerificationTimeout
 }
-
@@ -1,75 +1,151 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
-	"time"
+	"sync"
 
 	"github.com/ikeboy003/robinstock-go/models"
 )
 
-func getTokenFilePath(username string) string {
+// ErrTokenNotFound is returned by TokenStore.Load when no token is stored
+// under the key.
+var ErrTokenNotFound = errors.New("token not found")
+
+// TokenStore persists credentials between logins. Keys are chosen by the
+// caller; Login and Logout use the username.
+type TokenStore interface {
+	Load(ctx context.Context, key string) (*models.Auth, error)
+	Save(ctx context.Context, key string, auth *models.Auth) error
+	Delete(ctx context.Context, key string) error
+}
+
+// FileStore stores each token as a JSON file in a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore that keeps tokens in dir. The directory
+// is created on first save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// DefaultFileStore returns the FileStore used when Login is given no
+// store: ~/.tokens.
+func DefaultFileStore() (*FileStore, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return ""
+		return nil, fmt.Errorf("could not determine home directory: %w", err)
 	}
-	tokensDir := filepath.Join(homeDir, ".tokens")
-	os.MkdirAll(tokensDir, 0700)
-	return filepath.Join(tokensDir, fmt.Sprintf("robinhood_%s.json", username))
+	return NewFileStore(filepath.Join(homeDir, ".tokens")), nil
 }
 
-func tryLoadStoredToken(username string) (*models.Auth, bool) {
-	filePath := getTokenFilePath(username)
-	if filePath == "" {
-		return nil, false
-	}
-
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil, false
+// Load reads the token stored under key.
+func (s *FileStore) Load(ctx context.Context, key string) (*models.Auth, error) {
+	content, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrTokenNotFound
 	}
-
-	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, false
+		return nil, err
 	}
 
 	var token models.Auth
 	if err := json.Unmarshal(content, &token); err != nil {
-		return nil, false
+		return nil, fmt.Errorf("decode token: %w", err)
 	}
+	return &token, nil
+}
 
-	if token.IsExpired() {
-		os.Remove(filePath)
-		return nil, false
+// Save writes auth under key with mode 0600.
+func (s *FileStore) Save(ctx context.Context, key string, auth *models.Auth) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
 	}
 
-	return &token, true
+	fileData, err := json.MarshalIndent(auth, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path(key), fileData, 0600)
 }
 
-func saveToken(username string, token *models.Auth) error {
-	filePath := getTokenFilePath(username)
-	if filePath == "" {
-		return fmt.Errorf("could not determine home directory")
+// Delete removes the token stored under key. Deleting a missing token is
+// not an error.
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
 	}
+	return err
+}
 
-	if token.IssuedAt.IsZero() {
-		token.IssuedAt = time.Now()
-	}
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("robinhood_%s.json", url.PathEscape(key)))
+}
 
-	fileData, err := json.MarshalIndent(token, "", "  ")
-	if err != nil {
-		return err
-	}
+// MemoryStore keeps tokens in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]models.Auth
+}
 
-	return os.WriteFile(filePath, fileData, 0600)
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tokens: make(map[string]models.Auth)}
 }
 
-func deleteToken(username string) {
-	filePath := getTokenFilePath(username)
-	if filePath != "" {
-		os.Remove(filePath)
+// Load returns a copy of the token stored under key.
+func (s *MemoryStore) Load(ctx context.Context, key string) (*models.Auth, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, ErrTokenNotFound
 	}
+	return &token, nil
+}
+
+// Save stores a copy of auth under key.
+func (s *MemoryStore) Save(ctx context.Context, key string, auth *models.Auth) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[key] = *auth
+	return nil
 }
 
+// Delete removes the token stored under key.
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, key)
+	return nil
+}
+
+// NoopStore stores nothing, so every Login authenticates from scratch.
+type NoopStore struct{}
+
+// Load always returns ErrTokenNotFound.
+func (NoopStore) Load(ctx context.Context, key string) (*models.Auth, error) {
+	return nil, ErrTokenNotFound
+}
+
+// Save discards auth.
+func (NoopStore) Save(ctx context.Context, key string, auth *models.Auth) error {
+	return nil
+}
+
+// Delete does nothing.
+func (NoopStore) Delete(ctx context.Context, key string) error {
+	return nil
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	totpPeriod = 30 * time.Second
+	totpDigits = 6
+	// totpModulus is 10^totpDigits.
+	totpModulus = 1000000
+	// totpEdgeWindow is how close to the end of its time step a generated
+	// code must be for a rejection to be blamed on the boundary.
+	totpEdgeWindow = 5 * time.Second
+)
+
+// GenerateTOTP returns the RFC 6238 code for secret at t, using the
+// parameters authenticator apps use for Robinhood: HMAC-SHA1, 30-second
+// steps and 6 digits. secret is base32 and may contain spaces, lower case
+// letters and padding.
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(totpPeriod/time.Second)))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, code%totpModulus), nil
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("invalid TOTP secret: empty")
+	}
+	return key, nil
+}
+
+// stepEnd returns when the time step containing t ends.
+func stepEnd(t time.Time) time.Time {
+	step := int64(totpPeriod / time.Second)
+	return time.Unix((t.Unix()/step+1)*step, 0)
+}
+
+// nearStepEdge reports whether a code generated at issued and rejected at
+// now may have expired in flight: it was issued within totpEdgeWindow of
+// its step's end, or its step had ended by the time it was rejected.
+func nearStepEdge(issued, now time.Time) bool {
+	end := stepEnd(issued)
+	return end.Sub(issued) <= totpEdgeWindow || !now.Before(end)
+}
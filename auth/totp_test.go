@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go"
+)
+
+// rfcSecret is the RFC 6238 SHA-1 test key "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTP(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := GenerateTOTP(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("GenerateTOTP at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+
+	spaced, err := GenerateTOTP("gezd gnbv gy3t qojq gezd gnbv gy3t qojq====", time.Unix(59, 0))
+	if err != nil || spaced != "287082" {
+		t.Errorf("lower case, spaced and padded secret = %s, %v", spaced, err)
+	}
+	if _, err := GenerateTOTP("not base32!", time.Now()); err == nil {
+		t.Error("accepted an invalid secret")
+	}
+}
+
+func TestNearStepEdge(t *testing.T) {
+	tests := []struct {
+		issued, rejected int64
+		want             bool
+	}{
+		{60, 61, false}, // start of a step
+		{74, 75, false}, // middle of a step
+		{85, 86, true},  // last 5 seconds
+		{89, 89, true},  // last second
+		{70, 90, true},  // step over before rejection
+		{84, 89, false}, // 6 seconds left, rejected inside the step
+	}
+	for _, tt := range tests {
+		if got := nearStepEdge(time.Unix(tt.issued, 0), time.Unix(tt.rejected, 0)); got != tt.want {
+			t.Errorf("nearStepEdge(%d, %d) = %v, want %v", tt.issued, tt.rejected, got, tt.want)
+		}
+	}
+}
+
+// mfaServer accepts only the code for the accepted time and answers other
+// codes with body and a 400.
+func mfaServer(t *testing.T, accepted time.Time, body string) (*httptest.Server, *[]string) {
+	t.Helper()
+	want, err := GenerateTOTP(rfcSecret, accepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var codes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		codes = append(codes, payload["mfa_code"])
+		mu.Unlock()
+		if payload["mfa_code"] != want {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(body))
+			return
+		}
+		w.Write([]byte(`{"access_token":"a","refresh_token":"r","token_type":"Bearer","expires_in":86400}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &codes
+}
+
+func loginAt(t *testing.T, srv *httptest.Server, at time.Time) error {
+	t.Helper()
+	client := robinstock_go.NewClient(robinstock_go.WithHosts(robinstock_go.Hosts{API: srv.URL}), robinstock_go.WithRetryPolicy(robinstock_go.NoRetry))
+	clock := func(o *loginConfig) { o.now = func() time.Time { return at } }
+	_, err := Login(context.Background(), client, "user", "pass", "", WithTOTPSecret(rfcSecret), WithTokenStore(NoopStore{}), clock)
+	return err
+}
+
+func TestLoginTOTPRetriesAtStepEdge(t *testing.T) {
+	issued := time.Unix(1700000008, 0) // 2s before the step ends
+	srv, codes := mfaServer(t, issued.Add(totpPeriod), `{"mfa_required":true,"mfa_type":"app"}`)
+	if err := loginAt(t, srv, issued); err != nil {
+		t.Fatal(err)
+	}
+	if len(*codes) != 2 {
+		t.Fatalf("sent codes %v, want a retry with the next step's code", *codes)
+	}
+}
+
+func TestLoginTOTPNoRetryMidStep(t *testing.T) {
+	issued := time.Unix(1699999995, 0) // 15s before the step ends
+	srv, codes := mfaServer(t, issued.Add(totpPeriod), `{"mfa_required":true,"mfa_type":"app"}`)
+	if err := loginAt(t, srv, issued); err == nil {
+		t.Fatal("login succeeded with a rejected code")
+	}
+	if len(*codes) != 1 {
+		t.Fatalf("sent codes %v, want no retry mid-step", *codes)
+	}
+}
+
+func TestLoginTOTPFieldErrorRetries(t *testing.T) {
+	issued := time.Unix(1700000009, 0)
+	srv, codes := mfaServer(t, issued.Add(totpPeriod), `{"mfa_code":["Please enter a valid code."]}`)
+	if err := loginAt(t, srv, issued); err != nil {
+		t.Fatal(err)
+	}
+	if len(*codes) != 2 {
+		t.Fatalf("sent codes %v, want a retry", *codes)
+	}
+}
+
+// TestLoginTOTPUnrelatedErrorNoRetry checks that a 400 mentioning a "code"
+// in its detail but not about the MFA code is not treated as a rejection.
+func TestLoginTOTPUnrelatedErrorNoRetry(t *testing.T) {
+	issued := time.Unix(1700000009, 0)
+	srv, codes := mfaServer(t, time.Unix(0, 0), `{"detail":"Unable to log in with provided credentials. Error code 17."}`)
+	if err := loginAt(t, srv, issued); err == nil {
+		t.Fatal("login succeeded")
+	}
+	if len(*codes) != 1 {
+		t.Fatalf("sent codes %v, want no retry for a non-MFA error", *codes)
+	}
+}
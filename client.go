@@ -2,18 +2,19 @@ package robinstock_go
 
 import (
 	"bytes"
-	"compress/gzip"
-	"compress/zlib"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/ikeboy003/robinstock-go/models"
 	"github.com/ikeboy003/robinstock-go/utils"
 )
@@ -27,15 +28,39 @@ var (
 	ErrInvalidResponse  = errors.New("invalid response from API")
 )
 
+// Doer executes HTTP requests. *http.Client satisfies it, as do proxies,
+// recorders and test fakes.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 // Client represents a Robinhood API client.
+//
+// A Client is safe for concurrent use. Requests, SetAuth, SetTimeout, Use
+// and token refreshes may run from any number of goroutines: credentials are
+// swapped atomically, and a request uses the credentials, transport and
+// middleware in place when it starts. Settings applied through Options are
+// fixed once NewClient returns. A *models.Auth passed to SetAuth or returned
+// by GetAuth must not be modified afterwards; install a new one instead.
 type Client struct {
-	httpClient        *http.Client
-	phoenixHTTPClient *http.Client
-	auth              *models.Auth
+	// mu guards the transports and middleware, which are replaced rather
+	// than modified in place.
+	mu                sync.RWMutex
+	httpClient        Doer
+	phoenixHTTPClient Doer
+	middleware        []Middleware
+
+	hosts       Hosts
+	readOnly    bool
+	retryPolicy RetryPolicy
+	limiter     rateLimiter
+	logger      *slog.Logger
+	refresher   tokenRefresher
+	auth        atomic.Pointer[models.Auth]
 }
 
-// NewClient creates a new Robinhood API client.
-func NewClient() *Client {
+// NewClient creates a new Robinhood API client configured by opts.
+func NewClient(opts ...Option) *Client {
 	// Standard client for most endpoints
 	standardClient := &http.Client{
 		Timeout: defaultTimeout,
@@ -60,81 +85,196 @@ func NewClient() *Client {
 		Transport: phoenixTransport,
 	}
 
-	return &Client{
+	c := &Client{
 		httpClient:        standardClient,
 		phoenixHTTPClient: phoenixClient,
+		hosts:             DefaultHosts(),
+		retryPolicy:       DefaultRetryPolicy(),
+		logger:            discardLogger,
+		refresher:         tokenRefresher{window: defaultRefreshWindow},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// SetTimeout sets the HTTP request timeout.
+// SetTimeout sets the HTTP request timeout. Transports are copied rather than
+// modified, so requests already in flight and *http.Clients passed to
+// WithHTTPClient are unaffected. It has no effect on a Doer that is not an
+// *http.Client.
 func (c *Client) SetTimeout(timeout time.Duration) {
-	c.httpClient.Timeout = timeout
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.httpClient = withTimeout(c.httpClient, timeout)
+	c.phoenixHTTPClient = withTimeout(c.phoenixHTTPClient, timeout)
+}
+
+func withTimeout(doer Doer, timeout time.Duration) Doer {
+	hc, ok := doer.(*http.Client)
+	if !ok {
+		return doer
+	}
+	copied := *hc
+	copied.Timeout = timeout
+	return &copied
 }
 
 // SetAuth sets authentication credentials.
 func (c *Client) SetAuth(auth *models.Auth) {
-	c.auth = auth
+	c.auth.Store(auth)
 }
 
- // GetAuth returns the current authentication credentials.
+// GetAuth returns the current authentication credentials.
 func (c *Client) GetAuth() *models.Auth {
-	return c.auth
+	return c.auth.Load()
 }
 
 // IsAuthenticated returns true if the client is authenticated.
 func (c *Client) IsAuthenticated() bool {
-	return c.auth != nil && c.auth.AccessToken != ""
+	return isAuthenticated(c.GetAuth())
 }
 
-// doRequest executes an HTTP request with proper headers.
+func isAuthenticated(auth *models.Auth) bool {
+	return auth != nil && auth.AccessToken != ""
+}
+
+// doRequest executes a request through the client's middleware chain.
 func (c *Client) doRequest(ctx context.Context, method, urlStr string, body interface{}, authenticated bool) (*models.Response, error) {
-	var bodyReader io.Reader
-	if body != nil {
-		jsonBytes, err := json.Marshal(body)
+	return c.Do(ctx, &Request{
+		Method:        method,
+		URL:           urlStr,
+		Body:          body,
+		Authenticated: authenticated,
+	})
+}
+
+// send is the innermost Handler of the middleware chain. Authenticated
+// requests refresh the access token before it expires and are replayed once
+// after a 401 if a refresh succeeds.
+func (c *Client) send(ctx context.Context, r *Request) (*models.Response, error) {
+	if !r.Authenticated {
+		return c.sendAttempts(ctx, r)
+	}
+
+	c.refreshIfExpiring(ctx)
+
+	auth := c.GetAuth()
+	resp, err := c.sendAttempts(ctx, r)
+	if errors.Is(err, ErrUnauthorized) && c.canRefresh(auth) {
+		if refreshErr := c.refreshAuth(ctx, auth); refreshErr != nil {
+			return nil, errors.Join(err, refreshErr)
+		}
+		c.logger.DebugContext(ctx, "replaying request after token refresh", "method", r.Method, "url", r.URL)
+		return c.sendAttempts(ctx, r)
+	}
+	return resp, err
+}
+
+// sendAttempts executes an HTTP request with proper headers, retrying
+// transient failures according to the client's RetryPolicy.
+func (c *Client) sendAttempts(ctx context.Context, r *Request) (*models.Response, error) {
+	var jsonBytes []byte
+	if r.Body != nil {
+		var err error
+		jsonBytes, err = json.Marshal(r.Body)
 		if err != nil {
 			return nil, fmt.Errorf("marshal body: %w", err)
 		}
+	}
+
+	if r.Authenticated && !c.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	// Use Phoenix client for phoenix.robinhood.com endpoints
+	c.mu.RLock()
+	httpClient := c.httpClient
+	if r.phoenix {
+		httpClient = c.phoenixHTTPClient
+	}
+	c.mu.RUnlock()
+
+	logger := c.logger.With("request_id", uuid.NewString(), "method", r.Method, "url", r.URL)
+	retryable := isIdempotent(r.Method, jsonBytes)
+	for attempt := 1; ; attempt++ {
+		req, err := c.newRequest(ctx, r, jsonBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.limiter.wait(ctx, r.URL); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := httpClient.Do(req)
+		if retryable && attempt < c.retryPolicy.MaxAttempts {
+			if delay, ok := c.retryPolicy.retryDelay(ctx, attempt, resp, err); ok {
+				status := 0
+				if resp != nil {
+					status = resp.StatusCode
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+				logger.WarnContext(ctx, "retrying request", "attempt", attempt, "status", status, "delay", delay, "error", err)
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		if err != nil {
+			logger.DebugContext(ctx, "request failed", "attempt", attempt, "duration", time.Since(start), "error", err)
+			return nil, fmt.Errorf("execute request: %w", err)
+		}
+		defer resp.Body.Close()
+		logger.DebugContext(ctx, "request completed", "attempt", attempt, "status", resp.StatusCode, "duration", time.Since(start))
+
+		return parseResponse(resp)
+	}
+}
+
+// newRequest builds a single attempt of a request. The body is re-read from
+// jsonBytes so the request can be replayed.
+func (c *Client) newRequest(ctx context.Context, r *Request, jsonBytes []byte) (*http.Request, error) {
+	var bodyReader io.Reader
+	if jsonBytes != nil {
 		bodyReader = bytes.NewReader(jsonBytes)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, urlStr, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	// Set standard headers
 	req.Header.Set("Accept", "*/*")
-	req.Header.Set("Accept-Encoding", "gzip,deflate,br")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 	req.Header.Set("Accept-Language", "en-US,en;q=1")
 	req.Header.Set("X-Robinhood-API-Version", models.ApiVersion)
 	req.Header.Set("Connection", "keep-alive")
 	req.Header.Set("User-Agent", "robinstock_go/1.0")
 
-	if body != nil {
+	if jsonBytes != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	for key, values := range r.Header {
+		req.Header[key] = values
+	}
+
 	// Add authentication if required
-	if authenticated {
-		if !c.IsAuthenticated() {
+	if r.Authenticated {
+		auth := c.GetAuth()
+		if !isAuthenticated(auth) {
 			return nil, ErrNotAuthenticated
 		}
-		req.Header.Set("Authorization", fmt.Sprintf("%s %s", c.auth.TokenType, c.auth.AccessToken))
+		req.Header.Set("Authorization", fmt.Sprintf("%s %s", auth.TokenType, auth.AccessToken))
 	}
 
-	// Use Phoenix client for phoenix.robinhood.com endpoints
-	httpClient := c.httpClient
-	if strings.Contains(urlStr, "phoenix.robinhood.com") {
-		httpClient = c.phoenixHTTPClient
-	}
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	return parseResponse(resp)
+	return req, nil
 }
 
 // Get executes a GET request.
@@ -150,6 +290,8 @@ func (c *Client) Post(ctx context.Context, urlStr string, body interface{}, auth
 	return c.doRequest(ctx, http.MethodPost, urlStr, body, authenticated)
 }
 
+// parseResponse decodes the response body. Non-2xx responses are returned as
+// an *APIError.
 func parseResponse(resp *http.Response) (*models.Response, error) {
 	// Read body
 	bodyBytes, err := io.ReadAll(resp.Body)
@@ -158,34 +300,35 @@ func parseResponse(resp *http.Response) (*models.Response, error) {
 	}
 
 	// Decompress if needed
-	var reader io.Reader = bytes.NewReader(bodyBytes)
 	encoding := resp.Header.Get("Content-Encoding")
-	switch encoding {
-	case "gzip":
-		reader, err = gzip.NewReader(reader)
-		if err != nil {
-			return nil, fmt.Errorf("gzip decompress: %w", err)
-		}
-	case "deflate":
-		reader, err = zlib.NewReader(reader)
-		if err != nil {
-			return nil, fmt.Errorf("deflate decompress: %w", err)
-		}
+	bodyBytes, err = decodeBody(encoding, bodyBytes)
+	if err != nil {
+		return nil, &DecodeError{ContentEncoding: encoding, StatusCode: resp.StatusCode, Err: err}
+	}
+
+	response := &models.Response{
+		StatusCode: resp.StatusCode,
+		Body:       bodyBytes,
 	}
 
 	// Decode JSON
-	var data map[string]interface{}
-	if err := json.NewDecoder(reader).Decode(&data); err != nil {
-		return nil, fmt.Errorf("decode json: %w", err)
+	var decodeErr error
+	if len(bytes.TrimSpace(bodyBytes)) > 0 {
+		decodeErr = json.Unmarshal(bodyBytes, &response.Data)
 	}
 
-	response := &models.Response{
-		StatusCode: resp.StatusCode,
-		Data:       data,
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if decodeErr != nil {
+			response = nil
+		}
+		return nil, newAPIError(resp, bodyBytes, response)
+	}
+	if decodeErr != nil {
+		return nil, &DecodeError{ContentEncoding: encoding, StatusCode: resp.StatusCode, Err: fmt.Errorf("json: %w", decodeErr)}
 	}
 
 	// Extract results if present (paginated response)
-	if results, ok := data["results"].([]interface{}); ok {
+	if results, ok := response.Data["results"].([]interface{}); ok {
 		for _, r := range results {
 			if m, ok := r.(map[string]interface{}); ok {
 				response.Results = append(response.Results, m)
@@ -199,24 +342,14 @@ func parseResponse(resp *http.Response) (*models.Response, error) {
 // FetchAllPages fetches all pages of a paginated response.
 func (c *Client) FetchAllPages(ctx context.Context, initialURL string, authenticated bool) ([]map[string]interface{}, error) {
 	var allResults []map[string]interface{}
-	nextURL := initialURL
-
-	for nextURL != "" {
-		resp, err := c.Get(ctx, nextURL, nil, authenticated)
-		if err != nil {
-			return nil, err
-		}
 
-		allResults = append(allResults, resp.Results...)
-
-		// Check for next page
-		if next, ok := resp.Data["next"].(string); ok && next != "" {
-			nextURL = next
-		} else {
-			nextURL = ""
-		}
+	p := c.Pages(initialURL, authenticated)
+	for p.Next(ctx) {
+		allResults = append(allResults, p.Results()...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
 	}
 
 	return allResults, nil
 }
-
@@ -0,0 +1,78 @@
+package robinstock_go
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// TestClientConcurrentUse exercises requests, auth swaps, timeout changes,
+// middleware registration and token refreshes from many goroutines at once.
+// Run it with -race.
+func TestClientConcurrentUse(t *testing.T) {
+	var refreshes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/token/" {
+			refreshes.Add(1)
+			w.Write([]byte(`{"access_token":"new","token_type":"Bearer","expires_in":86400}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithHosts(Hosts{API: srv.URL}))
+	c.SetAuth(&models.Auth{AccessToken: "old", TokenType: "Bearer", RefreshToken: "r"})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		i := i
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			c.Get(ctx, models.BaseURL+"/positions/", nil, true)
+		}()
+		go func() {
+			defer wg.Done()
+			c.SetAuth(&models.Auth{AccessToken: "old", TokenType: "Bearer", RefreshToken: "r"})
+		}()
+		go func() {
+			defer wg.Done()
+			c.SetTimeout(time.Duration(i+1) * time.Second)
+			c.IsAuthenticated()
+		}()
+		go func() {
+			defer wg.Done()
+			c.Use(func(next Handler) Handler { return next })
+			c.RefreshAuth(ctx)
+		}()
+	}
+	wg.Wait()
+
+	if refreshes.Load() == 0 {
+		t.Fatal("no token refresh reached the server")
+	}
+
+	c.SetAuth(&models.Auth{AccessToken: "old", TokenType: "Bearer", RefreshToken: "r"})
+	resp, err := c.Get(ctx, models.BaseURL+"/positions/", nil, true)
+	if err != nil {
+		t.Fatalf("Get after 401 refresh: %v", err)
+	}
+	if ok, _ := resp.Data["ok"].(bool); !ok {
+		t.Fatalf("unexpected response %v", resp.Data)
+	}
+	if got := c.GetAuth().AccessToken; got != "new" {
+		t.Fatalf("access token = %q, want refreshed token", got)
+	}
+}
@@ -0,0 +1,75 @@
+package robinstock_go
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// acceptEncoding lists exactly the content codings decodeBody understands.
+const acceptEncoding = "gzip, deflate, br"
+
+// DecodeError reports a response body that could not be decompressed or
+// parsed as JSON.
+type DecodeError struct {
+	// ContentEncoding is the Content-Encoding header of the response.
+	ContentEncoding string
+	StatusCode      int
+	Err             error
+}
+
+func (e *DecodeError) Error() string {
+	if e.ContentEncoding == "" {
+		return fmt.Sprintf("decode response (status %d): %v", e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("decode %s response (status %d): %v", e.ContentEncoding, e.StatusCode, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeBody reverses the content codings listed in a Content-Encoding
+// header, last applied first.
+func decodeBody(contentEncoding string, body []byte) ([]byte, error) {
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		var reader io.Reader
+		switch coding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				return nil, fmt.Errorf("gzip: %w", err)
+			}
+			reader = zr
+		case "deflate":
+			// RFC 9110 deflate is zlib-wrapped, but some servers send raw
+			// DEFLATE data.
+			if zr, err := zlib.NewReader(bytes.NewReader(body)); err == nil {
+				reader = zr
+			} else {
+				reader = flate.NewReader(bytes.NewReader(body))
+			}
+		case "br":
+			reader = brotli.NewReader(bytes.NewReader(body))
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %q", coding)
+		}
+
+		decoded, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", coding, err)
+		}
+		body = decoded
+	}
+	return body, nil
+}
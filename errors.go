@@ -0,0 +1,137 @@
+package robinstock_go
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+var (
+	ErrUnauthorized            = errors.New("unauthorized")
+	ErrNotFound                = errors.New("not found")
+	ErrRateLimited             = errors.New("rate limited")
+	ErrInsufficientBuyingPower = errors.New("insufficient buying power")
+	ErrMarketClosed            = errors.New("market closed")
+)
+
+// APIError is returned for any response with a non-2xx status code.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	// Detail is the "detail" message Robinhood returns, or the first
+	// non-field error when no detail is present.
+	Detail string
+	// FieldErrors maps request fields to the validation messages reported
+	// for them.
+	FieldErrors map[string][]string
+	// Body is the decompressed response body.
+	Body []byte
+	// Response is the decoded body, or nil if the body was not JSON.
+	Response *models.Response
+}
+
+func newAPIError(resp *http.Response, body []byte, response *models.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		Response:   response,
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.URL = resp.Request.URL.String()
+	}
+	if response == nil {
+		return apiErr
+	}
+
+	for key, val := range response.Data {
+		if key == "detail" {
+			apiErr.Detail, _ = val.(string)
+			continue
+		}
+		var messages []string
+		switch v := val.(type) {
+		case string:
+			messages = []string{v}
+		case []interface{}:
+			for _, m := range v {
+				if str, ok := m.(string); ok {
+					messages = append(messages, str)
+				}
+			}
+		}
+		if len(messages) > 0 {
+			if apiErr.FieldErrors == nil {
+				apiErr.FieldErrors = make(map[string][]string)
+			}
+			apiErr.FieldErrors[key] = messages
+		}
+	}
+	if apiErr.Detail == "" {
+		if nonField := apiErr.FieldErrors["non_field_errors"]; len(nonField) > 0 {
+			apiErr.Detail = nonField[0]
+		}
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if len(e.FieldErrors) > 0 {
+		fields := make([]string, 0, len(e.FieldErrors))
+		for field, messages := range e.FieldErrors {
+			if field != "non_field_errors" {
+				fields = append(fields, field+": "+strings.Join(messages, " "))
+			}
+		}
+		sort.Strings(fields)
+		if len(fields) > 0 {
+			msg += " (" + strings.Join(fields, "; ") + ")"
+		}
+	}
+	return msg
+}
+
+// Is reports whether the error matches one of the package sentinels.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrInsufficientBuyingPower:
+		return e.mentions("buying power")
+	case ErrMarketClosed:
+		return e.mentions("market is closed", "market closed", "outside of market hours")
+	}
+	return false
+}
+
+// mentions reports whether the detail or any field error contains one of
+// the phrases, ignoring case.
+func (e *APIError) mentions(phrases ...string) bool {
+	texts := []string{e.Detail}
+	for _, messages := range e.FieldErrors {
+		texts = append(texts, messages...)
+	}
+	for _, text := range texts {
+		text = strings.ToLower(text)
+		for _, phrase := range phrases {
+			if strings.Contains(text, phrase) {
+				return true
+			}
+		}
+	}
+	return false
+}
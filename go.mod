@@ -2,3 +2,8 @@ module github.com/ikeboy003/robinstock-go
 
 go 1.21
 
+require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/google/uuid v1.6.0
+	golang.org/x/crypto v0.20.0
+)
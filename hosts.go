@@ -0,0 +1,89 @@
+package robinstock_go
+
+import (
+	"strings"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// Hosts holds the base URLs a Client sends requests to. Each field is a
+// scheme and host with an optional path prefix, e.g. "http://127.0.0.1:8080".
+type Hosts struct {
+	API     string
+	Phoenix string
+	Bonfire string
+	Nummus  string
+}
+
+// DefaultHosts returns the production Robinhood hosts.
+func DefaultHosts() Hosts {
+	return Hosts{
+		API:     models.APIBaseURL,
+		Phoenix: models.PhoenixBaseURL,
+		Bonfire: models.BonfireBaseURL,
+		Nummus:  models.NummusBaseURL,
+	}
+}
+
+// WithHosts reroutes requests for the production hosts to the given base
+// URLs. Empty fields keep their defaults. When Phoenix is rerouted to a
+// server that does not present a phoenix.robinhood.com certificate, pair
+// this with WithPhoenixClient.
+func WithHosts(hosts Hosts) Option {
+	return func(c *Client) {
+		if hosts.API != "" {
+			c.hosts.API = strings.TrimSuffix(hosts.API, "/")
+		}
+		if hosts.Phoenix != "" {
+			c.hosts.Phoenix = strings.TrimSuffix(hosts.Phoenix, "/")
+		}
+		if hosts.Bonfire != "" {
+			c.hosts.Bonfire = strings.TrimSuffix(hosts.Bonfire, "/")
+		}
+		if hosts.Nummus != "" {
+			c.hosts.Nummus = strings.TrimSuffix(hosts.Nummus, "/")
+		}
+	}
+}
+
+// Hosts returns the base URLs the client is configured with.
+func (c *Client) Hosts() Hosts {
+	return c.hosts
+}
+
+// resolveURL rewrites a URL built against a production host to the host the
+// client is configured with. It also reports whether the URL targets
+// Phoenix, decided before rewriting since Phoenix and API may share a test
+// server.
+func (c *Client) resolveURL(urlStr string) (string, bool) {
+	routes := []struct{ from, to string }{
+		{models.APIBaseURL, c.hosts.API},
+		{models.PhoenixBaseURL, c.hosts.Phoenix},
+		{models.BonfireBaseURL, c.hosts.Bonfire},
+		{models.NummusBaseURL, c.hosts.Nummus},
+	}
+	for _, r := range routes {
+		if hasHostPrefix(urlStr, r.from) {
+			return r.to + urlStr[len(r.from):], r.from == models.PhoenixBaseURL
+		}
+	}
+	return urlStr, c.isPhoenix(urlStr)
+}
+
+// isPhoenix reports whether a URL already aimed at the configured hosts
+// targets Phoenix. Base URLs are compared with their paths and the longest
+// match wins; when Phoenix and API share a base URL the URL counts as API.
+func (c *Client) isPhoenix(urlStr string) bool {
+	if !hasHostPrefix(urlStr, c.hosts.Phoenix) {
+		return false
+	}
+	return !hasHostPrefix(urlStr, c.hosts.API) || len(c.hosts.Phoenix) > len(c.hosts.API)
+}
+
+func hasHostPrefix(urlStr, base string) bool {
+	if !strings.HasPrefix(urlStr, base) {
+		return false
+	}
+	rest := urlStr[len(base):]
+	return rest == "" || rest[0] == '/' || rest[0] == '?'
+}
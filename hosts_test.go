@@ -0,0 +1,74 @@
+package robinstock_go
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+type countingDoer struct {
+	calls atomic.Int32
+}
+
+func (d *countingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.calls.Add(1)
+	return http.DefaultClient.Do(req)
+}
+
+// TestHostsSharedServer routes API and Phoenix to one test server and checks
+// each request still goes through the right Doer.
+func TestHostsSharedServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	api, phoenix := &countingDoer{}, &countingDoer{}
+	c := NewClient(
+		WithHosts(Hosts{API: srv.URL, Phoenix: srv.URL}),
+		WithHTTPClient(api),
+		WithPhoenixClient(phoenix),
+	)
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, models.APIBaseURL+"/positions/", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, models.PhoenixBaseURL+"/accounts/unified", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, srv.URL+"/quotes/", nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := api.calls.Load(); got != 2 {
+		t.Errorf("API client calls = %d, want 2", got)
+	}
+	if got := phoenix.calls.Load(); got != 1 {
+		t.Errorf("Phoenix client calls = %d, want 1", got)
+	}
+}
+
+func TestIsPhoenixLongestPrefix(t *testing.T) {
+	tests := []struct {
+		api, phoenix, url string
+		want              bool
+	}{
+		{"http://a", "http://b", "http://b/accounts/", true},
+		{"http://a", "http://b", "http://a/accounts/", false},
+		{"http://x", "http://x", "http://x/accounts/", false},
+		{"http://x", "http://x/phoenix", "http://x/phoenix/accounts/", true},
+		{"http://x/api", "http://x", "http://x/api/accounts/", false},
+		{"http://x/api", "http://x", "http://x/accounts/", true},
+	}
+	for _, tt := range tests {
+		c := NewClient(WithHosts(Hosts{API: tt.api, Phoenix: tt.phoenix}))
+		if got := c.isPhoenix(tt.url); got != tt.want {
+			t.Errorf("isPhoenix(%q) with API %q, Phoenix %q = %v, want %v", tt.url, tt.api, tt.phoenix, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package robinstock_go
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger sets the structured logger used by the client and every
+// endpoint package. By default log output is discarded.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// Logger returns the client's logger. It is never nil.
+func (c *Client) Logger() *slog.Logger {
+	return c.logger
+}
+
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler drops every record; it stands in for slog.DiscardHandler,
+// which requires Go 1.24.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
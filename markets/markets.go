@@ -2,7 +2,6 @@ package markets
 
 import (
 	"context"
-	"log"
 	"strings"
 
 	"github.com/ikeboy003/robinstock-go"
@@ -14,7 +13,7 @@ import (
 
 // GetTopMoversSP500 returns top moving stocks in the S&P 500.
 func GetTopMoversSP500(ctx context.Context, client *robinstock_go.Client, direction string) ([]models.SPMover, error) {
-	log.Printf("GetTopMoversSP500: Fetching %s movers...\n", direction)
+	client.Logger().DebugContext(ctx, "fetching movers", "op", "GetTopMoversSP500", "direction", direction)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -22,14 +21,14 @@ func GetTopMoversSP500(ctx context.Context, client *robinstock_go.Client, direct
 
 	direction = strings.ToLower(strings.TrimSpace(direction))
 	if direction != "up" && direction != "down" {
-		log.Println("GetTopMoversSP500: Direction must be 'up' or 'down'")
+		client.Logger().WarnContext(ctx, "invalid direction, must be up or down", "op", "GetTopMoversSP500", "direction", direction)
 		return nil, nil
 	}
 
 	params := map[string]string{"direction": direction}
 	resp, err := client.Get(ctx, urls.MoversSP500URL(), params, true)
 	if err != nil {
-		log.Printf("GetTopMoversSP500: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetTopMoversSP500", "error", err)
 		return nil, err
 	}
 
@@ -48,13 +47,13 @@ func GetTopMoversSP500(ctx context.Context, client *robinstock_go.Client, direct
 		}
 	}
 
-	log.Printf("GetTopMoversSP500: Received %d movers\n", len(movers))
+	client.Logger().DebugContext(ctx, "received movers", "op", "GetTopMoversSP500", "count", len(movers))
 	return movers, nil
 }
 
 // GetTop100MostPopular returns the 100 most popular stocks with their quotes.
 func GetTop100MostPopular(ctx context.Context, client *robinstock_go.Client) ([]models.Quote, error) {
-	log.Println("GetTop100MostPopular: Fetching top 100...")
+	client.Logger().DebugContext(ctx, "fetching top 100", "op", "GetTop100MostPopular")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -62,7 +61,7 @@ func GetTop100MostPopular(ctx context.Context, client *robinstock_go.Client) ([]
 
 	resp, err := client.Get(ctx, urls.Top100MostPopularURL(), nil, true)
 	if err != nil {
-		log.Printf("GetTop100MostPopular: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetTop100MostPopular", "error", err)
 		return nil, err
 	}
 
@@ -76,7 +75,7 @@ func GetTop100MostPopular(ctx context.Context, client *robinstock_go.Client) ([]
 		}
 	}
 
-	log.Printf("GetTop100MostPopular: Found %d instruments\n", len(instrumentURLs))
+	client.Logger().DebugContext(ctx, "found instruments", "op", "GetTop100MostPopular", "count", len(instrumentURLs))
 
 	// Get symbols from instrument URLs
 	symbols := getSymbolsFromInstruments(ctx, client, instrumentURLs)
@@ -91,13 +90,13 @@ func GetTop100MostPopular(ctx context.Context, client *robinstock_go.Client) ([]
 		return nil, err
 	}
 
-	log.Printf("GetTop100MostPopular: Received %d quotes\n", len(quotes))
+	client.Logger().DebugContext(ctx, "received quotes", "op", "GetTop100MostPopular", "count", len(quotes))
 	return quotes, nil
 }
 
 // GetStocksByMarketTag returns stocks from a specific market category.
 func GetStocksByMarketTag(ctx context.Context, client *robinstock_go.Client, tag string) ([]models.Quote, error) {
-	log.Printf("GetStocksByMarketTag: Fetching stocks for tag '%s'...\n", tag)
+	client.Logger().DebugContext(ctx, "fetching stocks by tag", "op", "GetStocksByMarketTag", "tag", tag)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -105,7 +104,7 @@ func GetStocksByMarketTag(ctx context.Context, client *robinstock_go.Client, tag
 
 	resp, err := client.Get(ctx, urls.MarketCategoryURL(tag), nil, true)
 	if err != nil {
-		log.Printf("GetStocksByMarketTag: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetStocksByMarketTag", "error", err)
 		return nil, err
 	}
 
@@ -119,7 +118,7 @@ func GetStocksByMarketTag(ctx context.Context, client *robinstock_go.Client, tag
 		}
 	}
 
-	log.Printf("GetStocksByMarketTag: Found %d instruments\n", len(instrumentURLs))
+	client.Logger().DebugContext(ctx, "found instruments", "op", "GetStocksByMarketTag", "count", len(instrumentURLs))
 
 	// Get symbols from instrument URLs
 	symbols := getSymbolsFromInstruments(ctx, client, instrumentURLs)
@@ -134,68 +133,42 @@ func GetStocksByMarketTag(ctx context.Context, client *robinstock_go.Client, tag
 		return nil, err
 	}
 
-	log.Printf("GetStocksByMarketTag: Received %d quotes\n", len(quotes))
+	client.Logger().DebugContext(ctx, "received quotes", "op", "GetStocksByMarketTag", "count", len(quotes))
 	return quotes, nil
 }
 
 // GetMarkets returns all available markets.
 func GetMarkets(ctx context.Context, client *robinstock_go.Client) ([]models.Market, error) {
-	log.Println("GetMarkets: Fetching markets...")
+	client.Logger().DebugContext(ctx, "fetching markets", "op", "GetMarkets")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	results, err := client.FetchAllPages(ctx, urls.MarketsURL(), true)
+	markets, err := robinstock_go.FetchAllPagesTyped[models.Market](ctx, client, urls.MarketsURL(), true)
 	if err != nil {
-		log.Printf("GetMarkets: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetMarkets", "error", err)
 		return nil, err
 	}
 
-	var markets []models.Market
-	for _, result := range results {
-		market := models.Market{
-			URL:          utils.GetString(result, "url"),
-			TOTP:         utils.GetString(result, "totp"),
-			Acronym:      utils.GetString(result, "acronym"),
-			Name:         utils.GetString(result, "name"),
-			City:         utils.GetString(result, "city"),
-			Country:      utils.GetString(result, "country"),
-			Timezone:     utils.GetString(result, "timezone"),
-			Website:      utils.GetString(result, "website"),
-			OperatingMIC: utils.GetString(result, "operating_mic"),
-		}
-		markets = append(markets, market)
-	}
-
-	log.Printf("GetMarkets: Received %d markets\n", len(markets))
+	client.Logger().DebugContext(ctx, "received markets", "op", "GetMarkets", "count", len(markets))
 	return markets, nil
 }
 
 // GetMarketHours returns trading hours for a specific market and date.
 func GetMarketHours(ctx context.Context, client *robinstock_go.Client, market, date string) (*models.MarketHours, error) {
-	log.Printf("GetMarketHours: Fetching hours for %s on %s...\n", market, date)
+	client.Logger().DebugContext(ctx, "fetching market hours", "op", "GetMarketHours", "market", market, "date", date)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	resp, err := client.Get(ctx, urls.MarketHoursURL(market, date), nil, true)
+	hours, err := robinstock_go.GetJSON[models.MarketHours](ctx, client, urls.MarketHoursURL(market, date), nil, true)
 	if err != nil {
-		log.Printf("GetMarketHours: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetMarketHours", "error", err)
 		return nil, err
 	}
 
-	hours := &models.MarketHours{
-		IsOpen:           utils.GetBool(resp.Data, "is_open"),
-		OpensAt:          utils.GetString(resp.Data, "opens_at"),
-		ClosesAt:         utils.GetString(resp.Data, "closes_at"),
-		Date:             utils.GetString(resp.Data, "date"),
-		ExtendedOpensAt:  utils.GetString(resp.Data, "extended_opens_at"),
-		ExtendedClosesAt: utils.GetString(resp.Data, "extended_closes_at"),
-	}
-
-	log.Printf("GetMarketHours: Market is open: %v\n", hours.IsOpen)
 	return hours, nil
 }
 
@@ -208,39 +181,39 @@ func getMovementPct(moverMap map[string]interface{}) float64 {
 
 // GetEarnings returns earnings reports for all stocks.
 func GetEarnings(ctx context.Context, client *robinstock_go.Client) ([]map[string]interface{}, error) {
-	log.Println("GetEarnings: Fetching earnings reports...")
+	client.Logger().DebugContext(ctx, "fetching earnings reports", "op", "GetEarnings")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	url := models.BaseURL + "/marketdata/earnings/"
+	url := urls.EarningsURL()
 	results, err := client.FetchAllPages(ctx, url, true)
 	if err != nil {
-		log.Printf("GetEarnings: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetEarnings", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetEarnings: Retrieved %d earnings reports\n", len(results))
+	client.Logger().DebugContext(ctx, "retrieved earnings reports", "op", "GetEarnings", "count", len(results))
 	return results, nil
 }
 
 // GetEvents returns upcoming options events.
 func GetEvents(ctx context.Context, client *robinstock_go.Client) ([]map[string]interface{}, error) {
-	log.Println("GetEvents: Fetching market events...")
+	client.Logger().DebugContext(ctx, "fetching market events", "op", "GetEvents")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	url := models.BaseURL + "/options/events/"
+	url := urls.OptionEventsURL()
 	results, err := client.FetchAllPages(ctx, url, true)
 	if err != nil {
-		log.Printf("GetEvents: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetEvents", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetEvents: Retrieved %d events\n", len(results))
+	client.Logger().DebugContext(ctx, "retrieved events", "op", "GetEvents", "count", len(results))
 	return results, nil
 }
 
@@ -0,0 +1,75 @@
+package robinstock_go
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// Request is a call made through the client, before its body is encoded.
+// URL has already been rewritten to the client's configured hosts.
+type Request struct {
+	Method string
+	URL    string
+	// Body is the value that will be JSON-encoded as the request body.
+	// Middleware that audits or redacts it must work on a copy; whatever is
+	// left here is what gets sent.
+	Body interface{}
+	// Header holds extra headers. They are applied after the client's
+	// standard headers and may override them.
+	Header        http.Header
+	Authenticated bool
+
+	// phoenix is set by Do when URL targets the Phoenix host.
+	phoenix bool
+}
+
+// Handler executes a Request and returns the decoded response. Non-2xx
+// responses are reported as an *APIError.
+type Handler func(ctx context.Context, req *Request) (*models.Response, error)
+
+// Middleware wraps a Handler to observe, modify or short-circuit requests.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware registers middleware at construction time. See Client.Use.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.Use(mw...)
+	}
+}
+
+// Use appends middleware to the chain wrapped around every request. The
+// first middleware registered is the outermost. Retries and rate limiting
+// happen inside the chain, so middleware sees each call once.
+func (c *Client) Use(mw ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chain := make([]Middleware, 0, len(c.middleware)+len(mw))
+	chain = append(chain, c.middleware...)
+	c.middleware = append(chain, mw...)
+}
+
+// Do sends req through the middleware chain. Get and Post are shorthands
+// for it; Do is useful when a call needs extra headers. A read-only client
+// rejects mutating requests here, before any middleware runs.
+func (c *Client) Do(ctx context.Context, req *Request) (*models.Response, error) {
+	req.URL, req.phoenix = c.resolveURL(req.URL)
+	if err := c.checkReadOnly(req); err != nil {
+		return nil, err
+	}
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+
+	c.mu.RLock()
+	chain := c.middleware
+	c.mu.RUnlock()
+
+	h := Handler(c.send)
+	for i := len(chain) - 1; i >= 0; i-- {
+		h = chain[i](h)
+	}
+	return h(ctx, req)
+}
@@ -1,9 +1,22 @@
 package models
 
-var (
-	BaseURL = "https://api.robinhood.com"
+// Production hosts. Clients can reroute them individually with
+// robinstock_go.WithHosts.
+const (
+	APIBaseURL     = "https://api.robinhood.com"
+	PhoenixBaseURL = "https://phoenix.robinhood.com"
+	BonfireBaseURL = "https://bonfire.robinhood.com"
+	NummusBaseURL  = "https://nummus.robinhood.com"
 )
 
+// BaseURL is the API host request URLs are built on. Assigning it still
+// sends every client's API requests to that host; WithHosts only reroutes
+// URLs on APIBaseURL, so the two should not be combined.
+//
+// Deprecated: use robinstock_go.WithHosts to point a client at another
+// server.
+var BaseURL = APIBaseURL
+
 const (
 	ClientID   = "c82SH0WZOsabOXGP2sxqcj34FxkvfnWRZBKlBjFS"
 	ApiVersion = "1.431.4"
@@ -13,6 +26,9 @@ type OrderSide string
 type OrderType string
 type OrderTrigger string
 type TimeInForce string
+type MarketSession string
+type TrailType string
+type OrderState string
 
 const (
 	SideBuy  OrderSide = "buy"
@@ -28,5 +44,68 @@ const (
 	TIFGFD TimeInForce = "gfd"
 	TIFIOC TimeInForce = "ioc"
 	TIFOpg TimeInForce = "opg"
+
+	MarketHoursRegular  MarketSession = "regular_hours"
+	MarketHoursExtended MarketSession = "extended_hours"
+	MarketHoursAllDay   MarketSession = "all_day_hours"
+
+	TrailTypeAmount     TrailType = "amount"
+	TrailTypePercentage TrailType = "percentage"
+
+	StateQueued          OrderState = "queued"
+	StateUnconfirmed     OrderState = "unconfirmed"
+	StateConfirmed       OrderState = "confirmed"
+	StatePartiallyFilled OrderState = "partially_filled"
+	StateFilled          OrderState = "filled"
+	StateRejected        OrderState = "rejected"
+	StateCancelled       OrderState = "cancelled"
+	StateFailed          OrderState = "failed"
+	StateVoided          OrderState = "voided"
 )
 
+// Valid reports whether s is a known side.
+func (s OrderSide) Valid() bool {
+	return s == SideBuy || s == SideSell
+}
+
+// Valid reports whether t is a known order type.
+func (t OrderType) Valid() bool {
+	return t == TypeMarket || t == TypeLimit
+}
+
+// Valid reports whether t is a known time in force.
+func (t TimeInForce) Valid() bool {
+	switch t {
+	case TIFGTC, TIFGFD, TIFIOC, TIFOpg:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether h is a known trading session.
+func (h MarketSession) Valid() bool {
+	return h == MarketHoursRegular || h == MarketHoursExtended || h == MarketHoursAllDay
+}
+
+// Valid reports whether t is a known trailing stop type.
+func (t TrailType) Valid() bool {
+	return t == TrailTypeAmount || t == TrailTypePercentage
+}
+
+// IsTerminal reports whether an order in state s can no longer change.
+func (s OrderState) IsTerminal() bool {
+	switch s {
+	case StateFilled, StateRejected, StateCancelled, StateFailed, StateVoided:
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether an order in state s is still working and may fill.
+func (s OrderState) IsOpen() bool {
+	switch s {
+	case StateQueued, StateUnconfirmed, StateConfirmed, StatePartiallyFilled:
+		return true
+	}
+	return false
+}
@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Auth struct {
 	AccessToken  string    `json:"access_token"`
@@ -19,6 +25,30 @@ func (a *Auth) IsExpired() bool {
 	return time.Now().After(expiresAt)
 }
 
+// ExpiresAt returns when the access token expires, or the zero time if the
+// expiry is unknown.
+func (a *Auth) ExpiresAt() time.Time {
+	if a.ExpiresIn == 0 || a.IssuedAt.IsZero() {
+		return time.Time{}
+	}
+	return a.IssuedAt.Add(time.Duration(a.ExpiresIn) * time.Second)
+}
+
+// ExpiresWithin reports whether the access token expires within d. It is
+// false when the expiry is unknown.
+func (a *Auth) ExpiresWithin(d time.Duration) bool {
+	expiresAt := a.ExpiresAt()
+	if expiresAt.IsZero() {
+		return false
+	}
+	return time.Now().Add(d).After(expiresAt)
+}
+
+// OrderRequest describes a stock order for orders.Submit. Type defaults to
+// limit when Price is set and market otherwise, TimeInForce to gtc and
+// MarketHours to regular hours. A StopPrice turns a market order into a stop
+// loss and a limit order into a stop limit. TrailAmount, with TrailType,
+// makes a trailing stop and excludes Price and StopPrice.
 type OrderRequest struct {
 	Symbol        string
 	Quantity      float64
@@ -29,30 +59,90 @@ type OrderRequest struct {
 	TimeInForce   TimeInForce
 	ExtendedHours bool
 	AccountNumber *string
+	MarketHours   MarketSession
+	TrailAmount   *float64
+	TrailType     TrailType
+}
+
+// OrderPreview is an order that was built and validated but not submitted.
+type OrderPreview struct {
+	// URL is the endpoint the order would be POSTed to.
+	URL string
+	// Payload is the exact JSON body that would be sent.
+	Payload map[string]interface{}
+	// Warnings describe adjustments made to the order, such as a market buy
+	// sent as a collared limit order, and data that could not be fetched.
+	Warnings []string
+}
+
+// OrderChanges describes a replacement for an open order in orders.Replace
+// and orders.ReplaceOptionOrder. Unset fields keep the original's values.
+type OrderChanges struct {
+	// Price sets the limit price. On a market order it makes the
+	// replacement a limit order.
+	Price *float64
+	// StopPrice sets the stop price, making the replacement a stop order.
+	StopPrice *float64
+	// Quantity is the new total quantity. Whatever filled before the
+	// cancellation is subtracted from it.
+	Quantity    *float64
+	TimeInForce TimeInForce
 }
 
+// OrderFilter selects orders in orders.GetStockOrders and
+// orders.GetOptionOrders. Zero fields match everything and time bounds are
+// inclusive.
+type OrderFilter struct {
+	AccountNumber string
+	// States matches any of the listed states.
+	States []OrderState
+	// Symbol is resolved to its instrument for stock orders and matched
+	// against the chain symbol for option orders.
+	Symbol string
+	// Instrument is a stock instrument URL, or for option orders an option
+	// instrument URL held by any leg.
+	Instrument string
+	// Side matches stock orders by side and option orders by any leg's side.
+	Side          OrderSide
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+}
 type Response struct {
 	StatusCode int
 	Data       map[string]interface{}
 	Results    []map[string]interface{}
+	// Body is the decompressed JSON body, for decoding into typed models.
+	Body []byte
 }
 
 type Account struct {
-	URL                     string `json:"url"`
-	AccountNumber           string `json:"account_number"`
-	Type                    string `json:"type"`
-	CreatedAt               string `json:"created_at"`
+	URL                     string                 `json:"url"`
+	AccountNumber           string                 `json:"account_number"`
+	Type                    string                 `json:"type"`
+	CreatedAt               string                 `json:"created_at"`
+	UpdatedAt               string                 `json:"updated_at"`
+	Deactivated             bool                   `json:"deactivated"`
+	CashBalances            map[string]interface{} `json:"cash_balances"`
+	PortfolioURL            string                 `json:"portfolio"`
+	BuyingPower             string                 `json:"buying_power"`
+	MaxAchEarlyAccessAmount string                 `json:"max_ach_early_access_amount"`
+	SweepEnabled            bool                   `json:"sweep_enabled"`
+	InstantEligibility      InstantEligibility     `json:"instant_eligibility"`
+	CashHeldForOrders       string                 `json:"cash_held_for_orders"`
+	UnsettledFunds          string                 `json:"unsettled_funds"`
+	UnsettledDebit          string                 `json:"unsettled_debit"`
+}
+
+// InstantEligibility reports whether an account's deposits are available
+// before they settle.
+type InstantEligibility struct {
+	State                   string `json:"state"`
+	Reason                  string `json:"reason"`
+	ReinstatementDate       string `json:"reinstatement_date"`
+	AdditionalDepositNeeded string `json:"additional_deposit_needed"`
 	UpdatedAt               string `json:"updated_at"`
-	Deactivated             bool   `json:"deactivated"`
-	CashBalances            string `json:"cash_balances"`
-	PortfolioURL            string `json:"portfolio"`
-	BuyingPower             string `json:"buying_power"`
-	MaxAchEarlyAccessAmount string `json:"max_ach_early_access_amount"`
-	SweepEnabled            bool   `json:"sweep_enabled"`
-	InstantEligibility      bool   `json:"instant_eligibility"`
-	CashHeldForOrders       string `json:"cash_held_for_orders"`
-	UnsettledFunds          string `json:"unsettled_funds"`
-	UnsettledDebit          string `json:"unsettled_debit"`
 }
 
 // Position represents a stock position.
@@ -114,42 +204,219 @@ type Quote struct {
 	UpdatedAt                string `json:"updated_at"`
 }
 
-// Order represents a stock order.
+// Order represents a stock order. Prices and quantities are parsed from the
+// API's decimal strings; fields the API leaves null are zero.
 type Order struct {
-	ID                     string      `json:"id"`
-	URL                    string      `json:"url"`
-	Account                string      `json:"account"`
-	Instrument             string      `json:"instrument"`
-	Symbol                 string      `json:"symbol"`
-	Type                   string      `json:"type"`
-	Side                   string      `json:"side"`
-	TimeInForce            string      `json:"time_in_force"`
-	Trigger                string      `json:"trigger"`
-	Price                  string      `json:"price"`
-	StopPrice              string      `json:"stop_price"`
-	Quantity               string      `json:"quantity"`
-	AveragePrice           string      `json:"average_price"`
-	CumulativeQuantity     string      `json:"cumulative_quantity"`
-	State                  string      `json:"state"`
-	CreatedAt              string      `json:"created_at"`
-	UpdatedAt              string      `json:"updated_at"`
-	LastTransactionAt      string      `json:"last_transaction_at"`
-	Executions             []Execution `json:"executions"`
-	ExtendedHours          bool        `json:"extended_hours"`
-	OverrideDayTradeChecks bool        `json:"override_day_trade_checks"`
-	OverrideDtbpChecks     bool        `json:"override_dtbp_checks"`
-	RefID                  string      `json:"ref_id"`
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	Account      string `json:"account"`
+	Instrument   string `json:"instrument"`
+	InstrumentID string `json:"instrument_id"`
+	Symbol       string `json:"symbol"`
+	// Cancel is the URL to POST to cancel the order, or empty once the order
+	// can no longer be cancelled.
+	Cancel                 string        `json:"cancel"`
+	Type                   OrderType     `json:"type"`
+	Side                   OrderSide     `json:"side"`
+	TimeInForce            TimeInForce   `json:"time_in_force"`
+	Trigger                OrderTrigger  `json:"trigger"`
+	MarketHours            MarketSession `json:"market_hours"`
+	Price                  float64       `json:"price"`
+	StopPrice              float64       `json:"stop_price"`
+	Quantity               float64       `json:"quantity"`
+	AveragePrice           float64       `json:"average_price"`
+	CumulativeQuantity     float64       `json:"cumulative_quantity"`
+	Fees                   float64       `json:"fees"`
+	State                  OrderState    `json:"state"`
+	RejectReason           string        `json:"reject_reason"`
+	CreatedAt              time.Time     `json:"created_at"`
+	UpdatedAt              time.Time     `json:"updated_at"`
+	LastTransactionAt      time.Time     `json:"last_transaction_at"`
+	Executions             []Execution   `json:"executions"`
+	ExtendedHours          bool          `json:"extended_hours"`
+	OverrideDayTradeChecks bool          `json:"override_day_trade_checks"`
+	OverrideDtbpChecks     bool          `json:"override_dtbp_checks"`
+	RefID                  string        `json:"ref_id"`
+	// TrailingPeg is set for trailing stops; StopPrice is then only the
+	// stop's latest level.
+	TrailingPeg *TrailingPeg `json:"trailing_peg"`
+	// PresetPercentLimit is the price collar of a regular-hours buy. Such
+	// buys are placed as limit orders even when submitted as market orders.
+	PresetPercentLimit float64 `json:"preset_percent_limit"`
+}
+
+// RemainingQuantity returns the quantity not yet filled.
+func (o *Order) RemainingQuantity() float64 {
+	return o.Quantity - o.CumulativeQuantity
+}
+
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type order Order
+	aux := struct {
+		*order
+		Price              decimal `json:"price"`
+		StopPrice          decimal `json:"stop_price"`
+		Quantity           decimal `json:"quantity"`
+		AveragePrice       decimal `json:"average_price"`
+		CumulativeQuantity decimal `json:"cumulative_quantity"`
+		Fees               decimal `json:"fees"`
+		PresetPercentLimit decimal `json:"preset_percent_limit"`
+	}{order: (*order)(o)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	o.Price = float64(aux.Price)
+	o.StopPrice = float64(aux.StopPrice)
+	o.Quantity = float64(aux.Quantity)
+	o.AveragePrice = float64(aux.AveragePrice)
+	o.CumulativeQuantity = float64(aux.CumulativeQuantity)
+	o.Fees = float64(aux.Fees)
+	o.PresetPercentLimit = float64(aux.PresetPercentLimit)
+	return nil
+}
+
+// TrailingPeg is how far a trailing stop follows the price, in the terms of
+// OrderRequest: a dollar amount or a percentage.
+type TrailingPeg struct {
+	Type   TrailType
+	Amount float64
+}
+
+func (p *TrailingPeg) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Type  string `json:"type"`
+		Price struct {
+			Amount decimal `json:"amount"`
+		} `json:"price"`
+		Percentage decimal `json:"percentage"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	switch aux.Type {
+	case "price":
+		p.Type, p.Amount = TrailTypeAmount, float64(aux.Price.Amount)
+	case "percentage":
+		p.Type, p.Amount = TrailTypePercentage, float64(aux.Percentage)
+	default:
+		p.Type, p.Amount = TrailType(aux.Type), 0
+	}
+	return nil
 }
 
 // Execution represents an order execution.
 type Execution struct {
-	ID               string `json:"id"`
-	Price            string `json:"price"`
-	Quantity         string `json:"quantity"`
-	SettlementDate   string `json:"settlement_date"`
-	Timestamp        string `json:"timestamp"`
+	ID             string    `json:"id"`
+	Price          float64   `json:"price"`
+	Quantity       float64   `json:"quantity"`
+	SettlementDate string    `json:"settlement_date"`
+	Timestamp      time.Time `json:"timestamp"`
 }
 
+func (e *Execution) UnmarshalJSON(data []byte) error {
+	type execution Execution
+	aux := struct {
+		*execution
+		Price    decimal `json:"price"`
+		Quantity decimal `json:"quantity"`
+	}{execution: (*execution)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	e.Price = float64(aux.Price)
+	e.Quantity = float64(aux.Quantity)
+	return nil
+}
+
+// OptionOrder represents a single or multi-leg option order. Quantities are
+// in contracts.
+type OptionOrder struct {
+	ID            string `json:"id"`
+	RefID         string `json:"ref_id"`
+	AccountNumber string `json:"account_number"`
+	ChainID       string `json:"chain_id"`
+	ChainSymbol   string `json:"chain_symbol"`
+	// CancelURL is the URL to POST to cancel the order, or empty once the
+	// order can no longer be cancelled.
+	CancelURL         string       `json:"cancel_url"`
+	Direction         string       `json:"direction"`
+	Type              OrderType    `json:"type"`
+	TimeInForce       TimeInForce  `json:"time_in_force"`
+	Trigger           OrderTrigger `json:"trigger"`
+	Price             float64      `json:"price"`
+	StopPrice         float64      `json:"stop_price"`
+	Premium           float64      `json:"premium"`
+	ProcessedPremium  float64      `json:"processed_premium"`
+	Quantity          float64      `json:"quantity"`
+	ProcessedQuantity float64      `json:"processed_quantity"`
+	PendingQuantity   float64      `json:"pending_quantity"`
+	CanceledQuantity  float64      `json:"canceled_quantity"`
+	State             OrderState   `json:"state"`
+	OpeningStrategy   string       `json:"opening_strategy"`
+	ClosingStrategy   string       `json:"closing_strategy"`
+	Legs              []OptionLeg  `json:"legs"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// RemainingQuantity returns the number of contracts not yet filled.
+func (o *OptionOrder) RemainingQuantity() float64 {
+	return o.Quantity - o.ProcessedQuantity
+}
+
+func (o *OptionOrder) UnmarshalJSON(data []byte) error {
+	type optionOrder OptionOrder
+	aux := struct {
+		*optionOrder
+		Price             decimal `json:"price"`
+		StopPrice         decimal `json:"stop_price"`
+		Premium           decimal `json:"premium"`
+		ProcessedPremium  decimal `json:"processed_premium"`
+		Quantity          decimal `json:"quantity"`
+		ProcessedQuantity decimal `json:"processed_quantity"`
+		PendingQuantity   decimal `json:"pending_quantity"`
+		CanceledQuantity  decimal `json:"canceled_quantity"`
+	}{optionOrder: (*optionOrder)(o)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	o.Price = float64(aux.Price)
+	o.StopPrice = float64(aux.StopPrice)
+	o.Premium = float64(aux.Premium)
+	o.ProcessedPremium = float64(aux.ProcessedPremium)
+	o.Quantity = float64(aux.Quantity)
+	o.ProcessedQuantity = float64(aux.ProcessedQuantity)
+	o.PendingQuantity = float64(aux.PendingQuantity)
+	o.CanceledQuantity = float64(aux.CanceledQuantity)
+	return nil
+}
+
+// OptionLeg is one leg of an OptionOrder.
+type OptionLeg struct {
+	ID             string      `json:"id"`
+	Option         string      `json:"option"`
+	PositionEffect string      `json:"position_effect"`
+	RatioQuantity  int         `json:"ratio_quantity"`
+	Side           OrderSide   `json:"side"`
+	Executions     []Execution `json:"executions"`
+}
+
+// decimal decodes the API's decimal strings, plain numbers and null.
+type decimal float64
+
+func (d *decimal) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*d = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("parse decimal %s: %w", data, err)
+	}
+	*d = decimal(f)
+	return nil
+}
 // Portfolio represents portfolio data.
 type Portfolio struct {
 	URL                             string `json:"url"`
@@ -166,7 +433,7 @@ type Portfolio struct {
 	ExcessMaintenanceWithUnclearedDeposits string `json:"excess_maintenance_with_uncleared_deposits"`
 	EquityPreviousClose             string `json:"equity_previous_close"`
 	AdjustedEquityPreviousClose     string `json:"adjusted_equity_previous_close"`
-	Withdrawable                    string `json:"withdrawable"`
+	Withdrawable                    string `json:"withdrawable_amount"`
 	UnsettledFunds                  string `json:"unsettled_funds"`
 }
 
@@ -28,13 +28,22 @@ type MarketHours struct {
 	ExtendedClosesAt string `json:"extended_closes_at"`
 }
 
+// BasicProfile is the user's contact and identity details from
+// /user/basic_info/. Names and email are on UserProfile.
 type BasicProfile struct {
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	Email       string `json:"email"`
-	PhoneNumber string `json:"phone_number"`
-	Username    string `json:"username"`
-	CreatedAt   string `json:"created_at"`
+	User               string `json:"user"`
+	Address            string `json:"address"`
+	City               string `json:"city"`
+	State              string `json:"state"`
+	Zipcode            string `json:"zipcode"`
+	PhoneNumber        string `json:"phone_number"`
+	MaritalStatus      string `json:"marital_status"`
+	DateOfBirth        string `json:"date_of_birth"`
+	Citizenship        string `json:"citizenship"`
+	CountryOfResidence string `json:"country_of_residence"`
+	NumberDependents   int    `json:"number_dependents"`
+	TaxIDSSN           string `json:"tax_id_ssn"`
+	UpdatedAt          string `json:"updated_at"`
 }
 
 type InvestmentProfile struct {
@@ -94,3 +103,14 @@ type Holding struct {
 	Percentage            float64 `json:"percentage"`
 	PortfolioPercentage   float64 `json:"portfolio_percentage"`
 }
+
+// Challenge is an SMS or email verification code Robinhood requires
+// before completing a login.
+type Challenge struct {
+	ID                string `json:"id"`
+	Type              string `json:"type"`
+	Status            string `json:"status"`
+	RemainingAttempts int    `json:"remaining_attempts"`
+	RemainingRetries  int    `json:"remaining_retries"`
+	ExpiresAt         string `json:"expires_at"`
+}
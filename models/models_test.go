@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// decodeFixture decodes testdata/name, a response captured from the API with
+// personal details replaced, into a T.
+func decodeFixture[T any](t *testing.T, name string) T {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	return v
+}
+
+func TestDecodeAccount(t *testing.T) {
+	a := decodeFixture[Account](t, "account.json")
+	if a.AccountNumber != "5QR12345" || a.BuyingPower != "1523.4100" || a.PortfolioURL == "" {
+		t.Errorf("account fields = %+v", a)
+	}
+	if a.InstantEligibility.State != "ok" || a.InstantEligibility.AdditionalDepositNeeded != "0.0000" {
+		t.Errorf("instant eligibility = %+v", a.InstantEligibility)
+	}
+	if a.CashBalances != nil {
+		t.Errorf("cash balances = %v, want nil for null", a.CashBalances)
+	}
+}
+
+func TestDecodePortfolio(t *testing.T) {
+	p := decodeFixture[Portfolio](t, "portfolio.json")
+	if p.Equity != "9764.9600" || p.Withdrawable != "1523.4100" || p.StartDate != "2019-03-14" {
+		t.Errorf("portfolio fields = %+v", p)
+	}
+}
+
+func TestDecodeUserProfile(t *testing.T) {
+	u := decodeFixture[UserProfile](t, "user.json")
+	if u.Username != "jdoe" || u.FirstName != "Jane" || u.Email != "jdoe@example.com" {
+		t.Errorf("user fields = %+v", u)
+	}
+}
+
+func TestDecodeBasicProfile(t *testing.T) {
+	b := decodeFixture[BasicProfile](t, "basic_info.json")
+	if b.City != "Springfield" || b.PhoneNumber != "2175550100" || b.NumberDependents != 0 || b.DateOfBirth != "1990-01-01" {
+		t.Errorf("basic profile fields = %+v", b)
+	}
+}
+
+func TestDecodeInvestmentProfile(t *testing.T) {
+	p := decodeFixture[InvestmentProfile](t, "investment_profile.json")
+	if p.RiskTolerance != "med_risk_tolerance" || p.TotalNetWorth != "100000_199999" {
+		t.Errorf("investment profile fields = %+v", p)
+	}
+}
+
+func TestDecodeSecurityProfile(t *testing.T) {
+	p := decodeFixture[SecurityProfile](t, "additional_info.json")
+	if p.ControlPerson || p.ObjectToDisclosure {
+		t.Errorf("security profile fields = %+v", p)
+	}
+}
+
+func TestDecodePosition(t *testing.T) {
+	p := decodeFixture[Position](t, "position.json")
+	if p.Quantity != "12.50000000" || p.AccountNumber != "5QR12345" {
+		t.Errorf("position fields = %+v", p)
+	}
+}
+
+func TestDecodeInstrument(t *testing.T) {
+	i := decodeFixture[Instrument](t, "instrument.json")
+	if i.Symbol != "AAPL" || !i.Tradeable || i.ListDate != "1980-12-12" {
+		t.Errorf("instrument fields = %+v", i)
+	}
+}
+
+func TestDecodeFundamental(t *testing.T) {
+	f := decodeFixture[Fundamental](t, "fundamental.json")
+	if f.MarketCap != "2636198400000.000000" || f.PERatio != "26.517900" {
+		t.Errorf("fundamental fields = %+v", f)
+	}
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{`"170.00000000"`, 170},
+		{`"0.05"`, 0.05},
+		{`"490.00000000000000000"`, 490},
+		{`12.5`, 12.5},
+		{`3`, 3},
+		{`null`, 0},
+		{`""`, 0},
+		{`"-1.25"`, -1.25},
+	}
+	for _, tt := range tests {
+		var d decimal
+		if err := json.Unmarshal([]byte(tt.in), &d); err != nil {
+			t.Errorf("decode %s: %v", tt.in, err)
+			continue
+		}
+		if float64(d) != tt.want {
+			t.Errorf("decode %s = %v, want %v", tt.in, float64(d), tt.want)
+		}
+	}
+
+	for _, in := range []string{`"abc"`, `"1.2.3"`, `true`} {
+		var d decimal
+		if err := json.Unmarshal([]byte(in), &d); err == nil {
+			t.Errorf("decode %s: want error", in)
+		}
+	}
+}
+
+func TestDecodeOrder(t *testing.T) {
+	o := decodeFixture[Order](t, "order.json")
+
+	if o.State != StatePartiallyFilled || !o.State.IsOpen() || o.State.IsTerminal() {
+		t.Errorf("state = %q", o.State)
+	}
+	if o.Type != TypeLimit || o.Side != SideBuy || o.TimeInForce != TIFGFD || o.Trigger != TriggerImmediate || o.MarketHours != MarketHoursRegular {
+		t.Errorf("enums = %s %s %s %s %s", o.Type, o.Side, o.TimeInForce, o.Trigger, o.MarketHours)
+	}
+	if o.Price != 170 || o.StopPrice != 0 || o.Quantity != 10 || o.CumulativeQuantity != 3 || o.AveragePrice != 169.98 {
+		t.Errorf("decimals = price %v stop %v qty %v filled %v avg %v", o.Price, o.StopPrice, o.Quantity, o.CumulativeQuantity, o.AveragePrice)
+	}
+	if o.RemainingQuantity() != 7 {
+		t.Errorf("remaining = %v, want 7", o.RemainingQuantity())
+	}
+	if want := time.Date(2024, 5, 2, 13, 45, 12, 318262000, time.UTC); !o.CreatedAt.Equal(want) {
+		t.Errorf("created_at = %v, want %v", o.CreatedAt, want)
+	}
+	if o.RejectReason != "" {
+		t.Errorf("reject reason = %q, want empty for null", o.RejectReason)
+	}
+	if len(o.Executions) != 1 || o.Executions[0].Price != 169.98 || o.Executions[0].Quantity != 3 || o.Executions[0].Timestamp.IsZero() {
+		t.Errorf("executions = %+v", o.Executions)
+	}
+}
+
+func TestDecodeOptionOrder(t *testing.T) {
+	o := decodeFixture[OptionOrder](t, "option_order.json")
+
+	if o.State != StateFilled || !o.State.IsTerminal() {
+		t.Errorf("state = %q", o.State)
+	}
+	if o.Price != 2.45 || o.Premium != 245 || o.ProcessedPremium != 490 || o.Quantity != 2 || o.ProcessedQuantity != 2 || o.StopPrice != 0 {
+		t.Errorf("decimals = %+v", o)
+	}
+	if o.RemainingQuantity() != 0 || o.CancelURL != "" || o.ClosingStrategy != "" {
+		t.Errorf("remaining %v, cancel %q, closing %q", o.RemainingQuantity(), o.CancelURL, o.ClosingStrategy)
+	}
+	if len(o.Legs) != 1 {
+		t.Fatalf("legs = %+v", o.Legs)
+	}
+	leg := o.Legs[0]
+	if leg.Side != SideBuy || leg.RatioQuantity != 1 || leg.PositionEffect != "open" || len(leg.Executions) != 1 || leg.Executions[0].Price != 2.45 {
+		t.Errorf("leg = %+v", leg)
+	}
+}
+
+func TestDecodeTrailingOrder(t *testing.T) {
+	o := decodeFixture[Order](t, "trailing_order.json")
+
+	if o.TrailingPeg == nil || o.TrailingPeg.Type != TrailTypePercentage || o.TrailingPeg.Amount != 5 {
+		t.Fatalf("trailing peg = %+v", o.TrailingPeg)
+	}
+	if o.Price != 0 || o.AveragePrice != 0 || o.PresetPercentLimit != 0 || o.StopPrice != 161.5 {
+		t.Errorf("null decimals = price %v avg %v collar %v, stop %v", o.Price, o.AveragePrice, o.PresetPercentLimit, o.StopPrice)
+	}
+	if !o.LastTransactionAt.IsZero() {
+		t.Errorf("last_transaction_at = %v, want zero for null", o.LastTransactionAt)
+	}
+
+	var amount TrailingPeg
+	if err := json.Unmarshal([]byte(`{"type":"price","price":{"amount":"1.25","currency_code":"USD"}}`), &amount); err != nil {
+		t.Fatal(err)
+	}
+	if amount.Type != TrailTypeAmount || amount.Amount != 1.25 {
+		t.Errorf("price peg = %+v", amount)
+	}
+}
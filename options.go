@@ -0,0 +1,23 @@
+package robinstock_go
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the Doer used for all endpoints except Phoenix.
+func WithHTTPClient(doer Doer) Option {
+	return func(c *Client) {
+		if doer != nil {
+			c.httpClient = doer
+		}
+	}
+}
+
+// WithPhoenixClient sets the Doer used for phoenix.robinhood.com endpoints,
+// which by default require a TLS 1.2-only transport.
+func WithPhoenixClient(doer Doer) Option {
+	return func(c *Client) {
+		if doer != nil {
+			c.phoenixHTTPClient = doer
+		}
+	}
+}
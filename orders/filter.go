@@ -0,0 +1,215 @@
+package orders
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
+	"github.com/ikeboy003/robinstock-go/urls"
+)
+
+// openOrderLookback bounds how far back open orders are searched. Robinhood
+// cancels good-til-cancelled orders after 90 days, so nothing older can
+// still be open; the extra days absorb time zone and expiry-day slack.
+const openOrderLookback = 92 * 24 * time.Hour
+
+var openStates = []models.OrderState{
+	models.StateQueued,
+	models.StateUnconfirmed,
+	models.StateConfirmed,
+	models.StatePartiallyFilled,
+}
+
+// openOrderFilter selects the open orders of accountNumber.
+func openOrderFilter(accountNumber *string) models.OrderFilter {
+	filter := models.OrderFilter{
+		States:       openStates,
+		CreatedAfter: time.Now().Add(-openOrderLookback),
+	}
+	if accountNumber != nil {
+		filter.AccountNumber = *accountNumber
+	}
+	return filter
+}
+
+// GetStockOrders returns the stock orders matching filter. The account,
+// instrument, a lower date bound and a single state are sent to the API;
+// every filter is also checked locally, so results are exact either way. Orders
+// come back newest first, so with CreatedAfter set paging stops at the first
+// page reaching past it, whether or not the API applied the bound.
+func GetStockOrders(ctx context.Context, client *robinstock_go.Client, filter models.OrderFilter) ([]models.Order, error) {
+	client.Logger().DebugContext(ctx, "fetching stock orders", "op", "GetStockOrders")
+
+	if !client.IsAuthenticated() {
+		return nil, robinstock_go.ErrNotAuthenticated
+	}
+
+	if filter.Symbol != "" && filter.Instrument == "" {
+		instrumentURL, err := getInstrumentURL(ctx, client, filter.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		filter.Instrument = instrumentURL
+	}
+
+	params := filterParams(filter)
+	if filter.Instrument != "" {
+		params.Set("instrument", filter.Instrument)
+	}
+
+	p := client.Pages(withParams(urls.OrdersURL(nil, nil, nil), params), true)
+	matched, fetched, err := collectOrders(ctx, p, filter.CreatedAfter,
+		func(o *models.Order) time.Time { return o.CreatedAt },
+		func(o *models.Order) bool { return matchStockOrder(filter, o) })
+	if err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetStockOrders", "error", err)
+		return nil, err
+	}
+
+	client.Logger().DebugContext(ctx, "retrieved orders", "op", "GetStockOrders", "fetched", fetched, "matched", len(matched))
+	return matched, nil
+}
+
+// GetOptionOrders returns the option orders matching filter. The account,
+// a lower date bound and a single state are sent to the API; every filter
+// is also checked locally, so results are exact either way. As with GetStockOrders,
+// paging stops once the orders reach past CreatedAfter.
+func GetOptionOrders(ctx context.Context, client *robinstock_go.Client, filter models.OrderFilter) ([]models.OptionOrder, error) {
+	client.Logger().DebugContext(ctx, "fetching option orders", "op", "GetOptionOrders")
+
+	if !client.IsAuthenticated() {
+		return nil, robinstock_go.ErrNotAuthenticated
+	}
+
+	p := client.Pages(withParams(urls.OptionOrdersURL(nil, nil, nil), filterParams(filter)), true)
+	matched, fetched, err := collectOrders(ctx, p, filter.CreatedAfter,
+		func(o *models.OptionOrder) time.Time { return o.CreatedAt },
+		func(o *models.OptionOrder) bool { return matchOptionOrder(filter, o) })
+	if err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetOptionOrders", "error", err)
+		return nil, err
+	}
+
+	client.Logger().DebugContext(ctx, "retrieved orders", "op", "GetOptionOrders", "fetched", fetched, "matched", len(matched))
+	return matched, nil
+}
+
+// collectOrders pages through newest-first orders keeping those that match.
+// Once a page holds an order created before createdAfter, every later page
+// is older still, so paging stops there.
+func collectOrders[T any](ctx context.Context, p *robinstock_go.Pager, createdAfter time.Time, createdAt func(*T) time.Time, match func(*T) bool) ([]T, int, error) {
+	var matched []T
+	fetched := 0
+	for p.Next(ctx) {
+		page, err := robinstock_go.PageResults[T](p)
+		if err != nil {
+			return nil, fetched, err
+		}
+		fetched += len(page)
+
+		reachedBound := false
+		for i := range page {
+			if match(&page[i]) {
+				matched = append(matched, page[i])
+			}
+			if created := createdAt(&page[i]); !createdAfter.IsZero() && !created.IsZero() && created.Before(createdAfter) {
+				reachedBound = true
+			}
+		}
+		if reachedBound {
+			break
+		}
+	}
+	if err := p.Err(); err != nil {
+		return nil, fetched, err
+	}
+	return matched, fetched, nil
+}
+
+// filterParams returns the query parameters for the filters both order
+// endpoints support. The only date parameter sent is updated_at[gte], the
+// one urls.OrdersURL has always relied on; whether the API honours the
+// created_at and lte variants is unverified. An order is never updated
+// before it is created, so CreatedAfter narrows it too. All date bounds are
+// still checked locally.
+func filterParams(filter models.OrderFilter) url.Values {
+	params := url.Values{}
+	if filter.AccountNumber != "" {
+		params.Set("account_numbers", filter.AccountNumber)
+	}
+	if len(filter.States) == 1 {
+		params.Set("state", string(filter.States[0]))
+	}
+	since := filter.UpdatedAfter
+	if filter.CreatedAfter.After(since) {
+		since = filter.CreatedAfter
+	}
+	if !since.IsZero() {
+		params.Set("updated_at[gte]", since.UTC().Format(time.RFC3339))
+	}
+	return params
+}
+
+func withParams(base string, params url.Values) string {
+	if len(params) == 0 {
+		return base
+	}
+	return base + "?" + params.Encode()
+}
+
+// matchTimes checks the filter's date bounds. Orders missing a timestamp
+// only match when that bound is unset.
+func matchTimes(filter models.OrderFilter, createdAt, updatedAt time.Time) bool {
+	if !filter.CreatedAfter.IsZero() && createdAt.Before(filter.CreatedAfter) {
+		return false
+	}
+	if !filter.CreatedBefore.IsZero() && (createdAt.IsZero() || createdAt.After(filter.CreatedBefore)) {
+		return false
+	}
+	if !filter.UpdatedAfter.IsZero() && updatedAt.Before(filter.UpdatedAfter) {
+		return false
+	}
+	if !filter.UpdatedBefore.IsZero() && (updatedAt.IsZero() || updatedAt.After(filter.UpdatedBefore)) {
+		return false
+	}
+	return true
+}
+
+func matchStockOrder(filter models.OrderFilter, order *models.Order) bool {
+	if filter.AccountNumber != "" && order.Account != "" && !strings.HasSuffix(order.Account, "/"+filter.AccountNumber+"/") {
+		return false
+	}
+	if len(filter.States) > 0 && !slices.Contains(filter.States, order.State) {
+		return false
+	}
+	if filter.Instrument != "" && order.Instrument != filter.Instrument {
+		return false
+	}
+	if filter.Side != "" && order.Side != filter.Side {
+		return false
+	}
+	return matchTimes(filter, order.CreatedAt, order.UpdatedAt)
+}
+
+func matchOptionOrder(filter models.OrderFilter, order *models.OptionOrder) bool {
+	if filter.AccountNumber != "" && order.AccountNumber != "" && order.AccountNumber != filter.AccountNumber {
+		return false
+	}
+	if len(filter.States) > 0 && !slices.Contains(filter.States, order.State) {
+		return false
+	}
+	if filter.Symbol != "" && !strings.EqualFold(order.ChainSymbol, strings.TrimSpace(filter.Symbol)) {
+		return false
+	}
+	if filter.Instrument != "" && !slices.ContainsFunc(order.Legs, func(leg models.OptionLeg) bool { return leg.Option == filter.Instrument }) {
+		return false
+	}
+	if filter.Side != "" && !slices.ContainsFunc(order.Legs, func(leg models.OptionLeg) bool { return leg.Side == filter.Side }) {
+		return false
+	}
+	return matchTimes(filter, order.CreatedAt, order.UpdatedAt)
+}
@@ -0,0 +1,192 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// pagedOrders serves pages of orders newest first, linking each page to the
+// next with a cursor, and records which pages were requested.
+func pagedOrders(t *testing.T, pages [][]string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var requested []string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.RawQuery)
+		mu.Unlock()
+
+		page := 0
+		fmt.Sscan(r.URL.Query().Get("cursor"), &page)
+		next := "null"
+		if page+1 < len(pages) {
+			next = fmt.Sprintf(`"%s/orders/?cursor=%d"`, srv.URL, page+1)
+		}
+		results := ""
+		for i, order := range pages[page] {
+			if i > 0 {
+				results += ","
+			}
+			results += order
+		}
+		fmt.Fprintf(w, `{"next":%s,"results":[%s]}`, next, results)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func stockOrder(id string, state models.OrderState, created time.Time) string {
+	return fmt.Sprintf(`{"id":%q,"state":%q,"created_at":%q}`, id, state, created.UTC().Format(time.RFC3339))
+}
+
+func testClient(opts ...robinstock_go.Option) *robinstock_go.Client {
+	c := robinstock_go.NewClient(append([]robinstock_go.Option{robinstock_go.WithRetryPolicy(robinstock_go.NoRetry)}, opts...)...)
+	c.SetAuth(&models.Auth{AccessToken: "token", TokenType: "Bearer"})
+	return c
+}
+
+func TestCollectOrdersStopsAtCreatedAfter(t *testing.T) {
+	now := time.Now()
+	bound := now.Add(-24 * time.Hour)
+	srv, requested := pagedOrders(t, [][]string{
+		{stockOrder("a", models.StateConfirmed, now), stockOrder("b", models.StateFilled, now.Add(-time.Hour))},
+		{stockOrder("c", models.StateQueued, now.Add(-2*time.Hour)), stockOrder("d", models.StateConfirmed, now.Add(-48*time.Hour))},
+		{stockOrder("e", models.StateConfirmed, now.Add(-72*time.Hour))},
+	})
+
+	filter := models.OrderFilter{CreatedAfter: bound, States: openStates}
+	got, fetched, err := collectOrders(context.Background(), testClient().Pages(srv.URL+"/orders/", true), filter.CreatedAfter,
+		func(o *models.Order) time.Time { return o.CreatedAt },
+		func(o *models.Order) bool { return matchStockOrder(filter, o) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requested()) != 2 {
+		t.Errorf("requested pages %q, want paging to stop after the page reaching past CreatedAfter", requested())
+	}
+	if fetched != 4 {
+		t.Errorf("fetched = %d, want 4", fetched)
+	}
+	var ids []string
+	for _, o := range got {
+		ids = append(ids, o.ID)
+	}
+	if fmt.Sprint(ids) != "[a c]" {
+		t.Errorf("matched %v, want [a c]", ids)
+	}
+}
+
+func TestCollectOrdersWithoutBoundReadsAllPages(t *testing.T) {
+	old := time.Now().Add(-365 * 24 * time.Hour)
+	srv, requested := pagedOrders(t, [][]string{
+		{stockOrder("a", models.StateFilled, old)},
+		{stockOrder("b", models.StateFilled, old)},
+		{stockOrder("c", models.StateFilled, old)},
+	})
+
+	got, _, err := collectOrders(context.Background(), testClient().Pages(srv.URL+"/orders/", true), time.Time{},
+		func(o *models.Order) time.Time { return o.CreatedAt },
+		func(o *models.Order) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || len(requested()) != 3 {
+		t.Errorf("got %d orders from %d pages, want 3 from 3", len(got), len(requested()))
+	}
+}
+
+func TestCollectOrdersPageError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, _, err := collectOrders(context.Background(), testClient().Pages(srv.URL+"/orders/", true), time.Time{},
+		func(o *models.Order) time.Time { return o.CreatedAt },
+		func(o *models.Order) bool { return true })
+	if err == nil {
+		t.Fatal("want error from failing page")
+	}
+}
+
+func TestFilterParams(t *testing.T) {
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter models.OrderFilter
+		want   url.Values
+	}{
+		{"empty", models.OrderFilter{}, url.Values{}},
+		{
+			"single state and account",
+			models.OrderFilter{AccountNumber: "5QR12345", States: []models.OrderState{models.StateFilled}},
+			url.Values{"account_numbers": {"5QR12345"}, "state": {"filled"}},
+		},
+		{"several states stay local", models.OrderFilter{States: openStates}, url.Values{}},
+		{"created after", models.OrderFilter{CreatedAfter: created}, url.Values{"updated_at[gte]": {"2024-05-01T00:00:00Z"}}},
+		{
+			"later bound wins",
+			models.OrderFilter{CreatedAfter: created, UpdatedAfter: updated},
+			url.Values{"updated_at[gte]": {"2024-05-03T00:00:00Z"}},
+		},
+		{"upper bounds stay local", models.OrderFilter{CreatedBefore: updated, UpdatedBefore: updated}, url.Values{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterParams(tt.filter); got.Encode() != tt.want.Encode() {
+				t.Errorf("params = %s, want %s", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+func TestMatchStockOrder(t *testing.T) {
+	now := time.Now()
+	order := &models.Order{
+		Account:    "https://api.robinhood.com/accounts/5QR12345/",
+		Instrument: "https://api.robinhood.com/instruments/i1/",
+		Side:       models.SideBuy,
+		State:      models.StateConfirmed,
+		CreatedAt:  now.Add(-time.Hour),
+		UpdatedAt:  now,
+	}
+	tests := []struct {
+		name   string
+		filter models.OrderFilter
+		want   bool
+	}{
+		{"empty", models.OrderFilter{}, true},
+		{"account", models.OrderFilter{AccountNumber: "5QR12345"}, true},
+		{"other account", models.OrderFilter{AccountNumber: "5QR1234"}, false},
+		{"open states", models.OrderFilter{States: openStates}, true},
+		{"other state", models.OrderFilter{States: []models.OrderState{models.StateFilled}}, false},
+		{"instrument", models.OrderFilter{Instrument: "https://api.robinhood.com/instruments/i1/"}, true},
+		{"other side", models.OrderFilter{Side: models.SideSell}, false},
+		{"created after", models.OrderFilter{CreatedAfter: now.Add(-2 * time.Hour)}, true},
+		{"created too early", models.OrderFilter{CreatedAfter: now.Add(-time.Minute)}, false},
+		{"updated before", models.OrderFilter{UpdatedBefore: now.Add(-time.Minute)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchStockOrder(tt.filter, order); got != tt.want {
+				t.Errorf("match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
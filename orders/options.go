@@ -3,100 +3,91 @@ package orders
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
 	"github.com/ikeboy003/robinstock-go/urls"
 	"github.com/ikeboy003/robinstock-go/utils"
 )
 
 // GetAllOptionOrders returns all option orders for an account.
-func GetAllOptionOrders(ctx context.Context, client *robinstock_go.Client, accountNumber, startDate *string) ([]map[string]interface{}, error) {
-	log.Println("GetAllOptionOrders: Fetching option orders...")
+func GetAllOptionOrders(ctx context.Context, client *robinstock_go.Client, accountNumber, startDate *string) ([]models.OptionOrder, error) {
+	client.Logger().DebugContext(ctx, "fetching option orders", "op", "GetAllOptionOrders")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
 	url := urls.OptionOrdersURL(nil, accountNumber, startDate)
-	results, err := client.FetchAllPages(ctx, url, true)
+	orders, err := robinstock_go.FetchAllPagesTyped[models.OptionOrder](ctx, client, url, true)
 	if err != nil {
-		log.Printf("GetAllOptionOrders: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetAllOptionOrders", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetAllOptionOrders: Retrieved %d orders\n", len(results))
-	return results, nil
+	client.Logger().DebugContext(ctx, "retrieved orders", "op", "GetAllOptionOrders", "count", len(orders))
+	return orders, nil
 }
 
-// GetAllOpenOptionOrders returns all open option orders.
-func GetAllOpenOptionOrders(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]map[string]interface{}, error) {
-	log.Println("GetAllOpenOptionOrders: Fetching open option orders...")
-
-	if !client.IsAuthenticated() {
-		return nil, robinstock_go.ErrNotAuthenticated
-	}
-
-	url := urls.OptionOrdersURL(nil, accountNumber, nil)
-	results, err := client.FetchAllPages(ctx, url, true)
-	if err != nil {
-		log.Printf("GetAllOpenOptionOrders: Error: %v\n", err)
-		return nil, err
-	}
-
-	var openOrders []map[string]interface{}
-	for _, order := range results {
-		if utils.GetString(order, "cancel_url") != "" {
-			openOrders = append(openOrders, order)
-		}
-	}
+// OptionOrderPages returns a Pager over an account's option orders, newest
+// first, fetching pages only as they are consumed. Decode pages with
+// robinstock_go.PageResults[models.OptionOrder].
+func OptionOrderPages(client *robinstock_go.Client, accountNumber, startDate *string, opts ...robinstock_go.PagerOption) *robinstock_go.Pager {
+	return client.Pages(urls.OptionOrdersURL(nil, accountNumber, startDate), true, opts...)
+}
 
-	log.Printf("GetAllOpenOptionOrders: Retrieved %d open orders\n", len(openOrders))
-	return openOrders, nil
+// GetAllOpenOptionOrders returns all open option orders, judged by state.
+// Only orders created within the last 90 days are fetched, since older ones
+// have expired.
+func GetAllOpenOptionOrders(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]models.OptionOrder, error) {
+	client.Logger().DebugContext(ctx, "fetching open option orders", "op", "GetAllOpenOptionOrders")
+	return GetOptionOrders(ctx, client, openOrderFilter(accountNumber))
 }
 
 // GetOptionOrderInfo returns information for a specific option order.
-func GetOptionOrderInfo(ctx context.Context, client *robinstock_go.Client, orderID string) (map[string]interface{}, error) {
-	log.Printf("GetOptionOrderInfo: Fetching order %s...\n", orderID)
+func GetOptionOrderInfo(ctx context.Context, client *robinstock_go.Client, orderID string) (*models.OptionOrder, error) {
+	client.Logger().DebugContext(ctx, "fetching order", "op", "GetOptionOrderInfo", "order_id", orderID)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	url := urls.OptionOrdersURL(&orderID, nil, nil)
-	resp, err := client.Get(ctx, url, nil, true)
+	order, err := robinstock_go.GetJSON[models.OptionOrder](ctx, client, urls.OptionOrdersURL(&orderID, nil, nil), nil, true)
 	if err != nil {
-		log.Printf("GetOptionOrderInfo: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetOptionOrderInfo", "error", err)
 		return nil, err
 	}
 
-	return resp.Data, nil
+	return order, nil
 }
 
-// CancelOptionOrder cancels a specific option order.
-func CancelOptionOrder(ctx context.Context, client *robinstock_go.Client, orderID string) (map[string]interface{}, error) {
-	log.Printf("CancelOptionOrder: Cancelling order %s...\n", orderID)
+// CancelOptionOrder requests cancellation of a specific option order and
+// returns the order as it stands afterwards. The cancel endpoint returns no
+// body, so the order is fetched again; its state may still be open until the
+// cancellation is processed.
+func CancelOptionOrder(ctx context.Context, client *robinstock_go.Client, orderID string) (*models.OptionOrder, error) {
+	client.Logger().InfoContext(ctx, "cancelling order", "op", "CancelOptionOrder", "order_id", orderID)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
 	url := urls.OptionCancelURL(orderID)
-	resp, err := client.Post(ctx, url, nil, true)
-	if err != nil {
-		log.Printf("CancelOptionOrder: Error: %v\n", err)
+	if _, err := client.Post(ctx, url, nil, true); err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "CancelOptionOrder", "error", err)
 		return nil, err
 	}
 
-	log.Printf("CancelOptionOrder: Order %s cancelled\n", orderID)
-	return resp.Data, nil
+	client.Logger().InfoContext(ctx, "order cancelled", "op", "CancelOptionOrder", "order_id", orderID)
+	return GetOptionOrderInfo(ctx, client, orderID)
 }
 
-// CancelAllOptionOrders cancels all open option orders.
-func CancelAllOptionOrders(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]map[string]interface{}, error) {
-	log.Println("CancelAllOptionOrders: Cancelling all open option orders...")
+// CancelAllOptionOrders cancels all open option orders and returns the
+// orders that were cancelled.
+func CancelAllOptionOrders(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]models.OptionOrder, error) {
+	client.Logger().InfoContext(ctx, "cancelling all open option orders", "op", "CancelAllOptionOrders")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -107,24 +98,28 @@ func CancelAllOptionOrders(ctx context.Context, client *robinstock_go.Client, ac
 		return nil, err
 	}
 
-	var cancelledOrders []map[string]interface{}
+	var cancelledOrders []models.OptionOrder
 	for _, order := range openOrders {
-		cancelURL := utils.GetString(order, "cancel_url")
-		if cancelURL != "" {
-			resp, err := client.Post(ctx, cancelURL, nil, true)
-			if err == nil {
-				cancelledOrders = append(cancelledOrders, resp.Data)
-			}
+		cancelURL := order.CancelURL
+		if cancelURL == "" {
+			cancelURL = urls.OptionCancelURL(order.ID)
+		}
+		if _, err := client.Post(ctx, cancelURL, nil, true); err != nil {
+			continue
+		}
+		if updated, err := GetOptionOrderInfo(ctx, client, order.ID); err == nil {
+			order = *updated
 		}
+		cancelledOrders = append(cancelledOrders, order)
 	}
 
-	log.Printf("CancelAllOptionOrders: Cancelled %d orders\n", len(cancelledOrders))
+	client.Logger().InfoContext(ctx, "cancelled orders", "op", "CancelAllOptionOrders", "count", len(cancelledOrders))
 	return cancelledOrders, nil
 }
 
 // GetAllOptionPositions returns all option positions ever held.
 func GetAllOptionPositions(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]map[string]interface{}, error) {
-	log.Println("GetAllOptionPositions: Fetching all option positions...")
+	client.Logger().DebugContext(ctx, "fetching all option positions", "op", "GetAllOptionPositions")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -133,17 +128,17 @@ func GetAllOptionPositions(ctx context.Context, client *robinstock_go.Client, ac
 	url := buildOptionPositionsURL(accountNumber)
 	results, err := client.FetchAllPages(ctx, url, true)
 	if err != nil {
-		log.Printf("GetAllOptionPositions: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetAllOptionPositions", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetAllOptionPositions: Retrieved %d positions\n", len(results))
+	client.Logger().DebugContext(ctx, "retrieved positions", "op", "GetAllOptionPositions", "count", len(results))
 	return results, nil
 }
 
 // GetOpenOptionPositions returns all open option positions.
 func GetOpenOptionPositions(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]map[string]interface{}, error) {
-	log.Println("GetOpenOptionPositions: Fetching open option positions...")
+	client.Logger().DebugContext(ctx, "fetching open option positions", "op", "GetOpenOptionPositions")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -154,7 +149,7 @@ func GetOpenOptionPositions(ctx context.Context, client *robinstock_go.Client, a
 
 	resp, err := client.Get(ctx, url, params, true)
 	if err != nil {
-		log.Printf("GetOpenOptionPositions: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetOpenOptionPositions", "error", err)
 		return nil, err
 	}
 
@@ -167,20 +162,20 @@ func GetOpenOptionPositions(ctx context.Context, client *robinstock_go.Client, a
 		}
 	}
 
-	log.Printf("GetOpenOptionPositions: Retrieved %d positions\n", len(positions))
+	client.Logger().DebugContext(ctx, "retrieved positions", "op", "GetOpenOptionPositions", "count", len(positions))
 	return positions, nil
 }
 
 // GetOptionChains returns the option chain for a symbol.
 func GetOptionChains(ctx context.Context, client *robinstock_go.Client, symbol string) (map[string]interface{}, error) {
-	log.Printf("GetOptionChains: Fetching chains for %s...\n", symbol)
+	client.Logger().DebugContext(ctx, "fetching option chains", "op", "GetOptionChains", "symbol", symbol)
 
 	symbol = strings.ToUpper(strings.TrimSpace(symbol))
 
-	url := fmt.Sprintf("https://api.robinhood.com/options/chains/?equity_instrument_ids=%s", symbol)
-	resp, err := client.Get(ctx, url, nil, false)
+	params := map[string]string{"equity_instrument_ids": symbol}
+	resp, err := client.Get(ctx, urls.OptionChainsURL(), params, false)
 	if err != nil {
-		log.Printf("GetOptionChains: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetOptionChains", "error", err)
 		return nil, err
 	}
 
@@ -189,13 +184,13 @@ func GetOptionChains(ctx context.Context, client *robinstock_go.Client, symbol s
 
 // GetOptionInstruments returns option instruments based on filters.
 func GetOptionInstruments(ctx context.Context, client *robinstock_go.Client, chainID, expirationDate, strikePrice, optionType *string) ([]map[string]interface{}, error) {
-	log.Println("GetOptionInstruments: Fetching option instruments...")
+	client.Logger().DebugContext(ctx, "fetching option instruments", "op", "GetOptionInstruments")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	url := "https://api.robinhood.com/options/instruments/"
+	url := urls.OptionInstrumentsURL()
 	params := make(map[string]string)
 
 	if chainID != nil {
@@ -213,33 +208,69 @@ func GetOptionInstruments(ctx context.Context, client *robinstock_go.Client, cha
 
 	results, err := client.FetchAllPages(ctx, url, true)
 	if err != nil {
-		log.Printf("GetOptionInstruments: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetOptionInstruments", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetOptionInstruments: Retrieved %d instruments\n", len(results))
+	client.Logger().DebugContext(ctx, "retrieved instruments", "op", "GetOptionInstruments", "count", len(results))
 	return results, nil
 }
 
 // OrderOptionBuyLimit places a limit buy order for an option.
-func OrderOptionBuyLimit(ctx context.Context, client *robinstock_go.Client, positionEffect, creditOrDebit string, price float64, symbol string, quantity int, expirationDate, strike, optionType string, accountNumber *string, timeInForce string) (map[string]interface{}, error) {
-	log.Printf("OrderOptionBuyLimit: Buying %d %s %s $%s %s...\n", quantity, symbol, expirationDate, strike, optionType)
+func OrderOptionBuyLimit(ctx context.Context, client *robinstock_go.Client, positionEffect, creditOrDebit string, price float64, symbol string, quantity int, expirationDate, strike, optionType string, accountNumber *string, timeInForce string) (*models.OptionOrder, error) {
+	client.Logger().InfoContext(ctx, "submitting option order", "op", "OrderOptionBuyLimit", "symbol", symbol, "quantity", quantity, "expiration_date", expirationDate, "strike", strike, "option_type", optionType)
 	return placeOptionOrder(ctx, client, "buy", positionEffect, creditOrDebit, price, 0, symbol, quantity, expirationDate, strike, optionType, accountNumber, timeInForce)
 }
 
 // OrderOptionSellLimit places a limit sell order for an option.
-func OrderOptionSellLimit(ctx context.Context, client *robinstock_go.Client, positionEffect, creditOrDebit string, price float64, symbol string, quantity int, expirationDate, strike, optionType string, accountNumber *string, timeInForce string) (map[string]interface{}, error) {
-	log.Printf("OrderOptionSellLimit: Selling %d %s %s $%s %s...\n", quantity, symbol, expirationDate, strike, optionType)
+func OrderOptionSellLimit(ctx context.Context, client *robinstock_go.Client, positionEffect, creditOrDebit string, price float64, symbol string, quantity int, expirationDate, strike, optionType string, accountNumber *string, timeInForce string) (*models.OptionOrder, error) {
+	client.Logger().InfoContext(ctx, "submitting option order", "op", "OrderOptionSellLimit", "symbol", symbol, "quantity", quantity, "expiration_date", expirationDate, "strike", strike, "option_type", optionType)
 	return placeOptionOrder(ctx, client, "sell", positionEffect, creditOrDebit, price, 0, symbol, quantity, expirationDate, strike, optionType, accountNumber, timeInForce)
 }
 
 // OrderOptionSpread places an option spread order.
-func OrderOptionSpread(ctx context.Context, client *robinstock_go.Client, direction string, price float64, symbol string, quantity int, spread []map[string]interface{}, accountNumber *string, timeInForce string) (map[string]interface{}, error) {
-	log.Printf("OrderOptionSpread: Placing %s spread for %s...\n", direction, symbol)
+func OrderOptionSpread(ctx context.Context, client *robinstock_go.Client, direction string, price float64, symbol string, quantity int, spread []map[string]interface{}, accountNumber *string, timeInForce string) (*models.OptionOrder, error) {
+	client.Logger().InfoContext(ctx, "submitting option spread", "op", "OrderOptionSpread", "symbol", symbol, "direction", direction)
+
+	preview, err := buildOptionSpread(ctx, client, direction, price, symbol, quantity, spread, accountNumber, timeInForce)
+	if err != nil {
+		return nil, err
+	}
+	logWarnings(ctx, client, "OrderOptionSpread", preview)
+
+	order, err := robinstock_go.PostJSON[models.OptionOrder](ctx, client, preview.URL, preview.Payload, true)
+	if err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "OrderOptionSpread", "error", err)
+		return nil, err
+	}
+
+	client.Logger().InfoContext(ctx, "order placed", "op", "OrderOptionSpread", "symbol", symbol, "order_id", order.ID)
+	return order, nil
+}
+
+// PreviewOptionSpread builds the payload OrderOptionSpread would submit,
+// resolving the account and every leg's option instrument, without sending
+// it.
+func PreviewOptionSpread(ctx context.Context, client *robinstock_go.Client, direction string, price float64, symbol string, quantity int, spread []map[string]interface{}, accountNumber *string, timeInForce string) (*models.OrderPreview, error) {
+	client.Logger().DebugContext(ctx, "previewing option spread", "op", "PreviewOptionSpread", "symbol", symbol, "direction", direction)
+	return buildOptionSpread(ctx, client, direction, price, symbol, quantity, spread, accountNumber, timeInForce)
+}
+
+// PreviewOptionOrder builds the payload placeOptionOrder would submit for
+// the same arguments, resolving the account and option instrument, without
+// sending it.
+func PreviewOptionOrder(ctx context.Context, client *robinstock_go.Client, side, positionEffect, creditOrDebit string, price, stopPrice float64, symbol string, quantity int, expirationDate, strike, optionType string, accountNumber *string, timeInForce string) (*models.OrderPreview, error) {
+	client.Logger().DebugContext(ctx, "previewing option order", "op", "PreviewOptionOrder", "symbol", symbol, "side", side, "quantity", quantity)
+	return buildOptionOrder(ctx, client, side, positionEffect, creditOrDebit, price, stopPrice, symbol, quantity, expirationDate, strike, optionType, accountNumber, timeInForce)
+}
 
+func buildOptionSpread(ctx context.Context, client *robinstock_go.Client, direction string, price float64, symbol string, quantity int, spread []map[string]interface{}, accountNumber *string, timeInForce string) (*models.OrderPreview, error) {
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
+	if len(spread) == 0 {
+		return nil, fmt.Errorf("spread has no legs")
+	}
 
 	symbol = strings.ToUpper(strings.TrimSpace(symbol))
 
@@ -262,7 +293,7 @@ func OrderOptionSpread(ctx context.Context, client *robinstock_go.Client, direct
 			"position_effect": utils.GetString(leg, "effect"),
 			"side":            utils.GetString(leg, "action"),
 			"ratio_quantity":  utils.GetInt(leg, "ratio_quantity"),
-			"option":          fmt.Sprintf("https://api.robinhood.com/options/instruments/%s/", optionID),
+			"option":          urls.OptionInstrumentURL(optionID),
 		})
 	}
 
@@ -280,18 +311,34 @@ func OrderOptionSpread(ctx context.Context, client *robinstock_go.Client, direct
 		"ref_id":                    uuid.NewString(),
 	}
 
-	url := urls.OptionOrdersURL(nil, accountNumber, nil)
-	resp, err := client.Post(ctx, url, payload, true)
+	return &models.OrderPreview{
+		URL:      urls.OptionOrdersURL(nil, accountNumber, nil),
+		Payload:  payload,
+		Warnings: priceRoundingWarnings(price, 0),
+	}, nil
+}
+
+func placeOptionOrder(ctx context.Context, client *robinstock_go.Client, side, positionEffect, creditOrDebit string, price, stopPrice float64, symbol string, quantity int, expirationDate, strike, optionType string, accountNumber *string, timeInForce string) (*models.OptionOrder, error) {
+	preview, err := buildOptionOrder(ctx, client, side, positionEffect, creditOrDebit, price, stopPrice, symbol, quantity, expirationDate, strike, optionType, accountNumber, timeInForce)
 	if err != nil {
-		log.Printf("OrderOptionSpread: Error: %v\n", err)
 		return nil, err
 	}
+	logWarnings(ctx, client, "placeOptionOrder", preview)
 
-	log.Printf("OrderOptionSpread: Order placed successfully. Order ID: %s\n", utils.GetString(resp.Data, "id"))
-	return resp.Data, nil
+	order, err := robinstock_go.PostJSON[models.OptionOrder](ctx, client, preview.URL, preview.Payload, true)
+	if err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "placeOptionOrder", "error", err)
+		return nil, err
+	}
+	if order.ID == "" {
+		return nil, fmt.Errorf("response has no order")
+	}
+
+	client.Logger().InfoContext(ctx, "order placed", "op", "placeOptionOrder", "symbol", symbol, "order_id", order.ID)
+	return order, nil
 }
 
-func placeOptionOrder(ctx context.Context, client *robinstock_go.Client, side, positionEffect, creditOrDebit string, price, stopPrice float64, symbol string, quantity int, expirationDate, strike, optionType string, accountNumber *string, timeInForce string) (map[string]interface{}, error) {
+func buildOptionOrder(ctx context.Context, client *robinstock_go.Client, side, positionEffect, creditOrDebit string, price, stopPrice float64, symbol string, quantity int, expirationDate, strike, optionType string, accountNumber *string, timeInForce string) (*models.OrderPreview, error) {
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
@@ -317,7 +364,7 @@ func placeOptionOrder(ctx context.Context, client *robinstock_go.Client, side, p
 				"position_effect": positionEffect,
 				"side":            side,
 				"ratio_quantity":  1,
-				"option":          fmt.Sprintf("https://api.robinhood.com/options/instruments/%s/", optionID),
+				"option":          urls.OptionInstrumentURL(optionID),
 			},
 		},
 		"type":                      "limit",
@@ -334,36 +381,27 @@ func placeOptionOrder(ctx context.Context, client *robinstock_go.Client, side, p
 		payload["stop_price"] = utils.RoundPrice(stopPrice)
 	}
 
-	url := urls.OptionOrdersURL(nil, accountNumber, nil)
-	resp, err := client.Post(ctx, url, payload, true)
-	if err != nil {
-		log.Printf("placeOptionOrder: Error: %v\n", err)
-		return nil, err
-	}
-
-	if resp == nil {
-		return nil, fmt.Errorf("response is nil")
-	}
+	return &models.OrderPreview{
+		URL:      urls.OptionOrdersURL(nil, accountNumber, nil),
+		Payload:  payload,
+		Warnings: priceRoundingWarnings(price, stopPrice),
+	}, nil
+}
 
-	if resp.StatusCode >= 400 {
-		errMsg := utils.GetString(resp.Data, "detail")
-		if errMsg == "" {
-			errMsg = fmt.Sprintf("HTTP %d", resp.StatusCode)
-		}
-		log.Printf("placeOptionOrder: Error: %s\n", errMsg)
-		return nil, fmt.Errorf("%s", errMsg)
+// priceRoundingWarnings reports option prices that were rounded to cents.
+func priceRoundingWarnings(price, stopPrice float64) []string {
+	var warnings []string
+	if rounded := utils.RoundPrice(price); rounded != price {
+		warnings = append(warnings, fmt.Sprintf("price %v rounded to %.2f", price, rounded))
 	}
-
-	if resp.Data == nil {
-		return nil, fmt.Errorf("response data is nil")
+	if rounded := utils.RoundPrice(stopPrice); stopPrice > 0 && rounded != stopPrice {
+		warnings = append(warnings, fmt.Sprintf("stop price %v rounded to %.2f", stopPrice, rounded))
 	}
-
-	log.Printf("placeOptionOrder: Order placed successfully. Order ID: %s\n", utils.GetString(resp.Data, "id"))
-	return resp.Data, nil
+	return warnings
 }
 
 func getOptionID(ctx context.Context, client *robinstock_go.Client, symbol, expirationDate, strike, optionType string) (string, error) {
-	url := "https://api.robinhood.com/options/instruments/"
+	url := urls.OptionInstrumentsURL()
 	params := map[string]string{
 		"chain_symbol":     symbol,
 		"expiration_dates": expirationDate,
@@ -387,7 +425,7 @@ func getOptionID(ctx context.Context, client *robinstock_go.Client, symbol, expi
 }
 
 func buildOptionPositionsURL(accountNumber *string) string {
-	url := "https://api.robinhood.com/options/positions/"
+	url := urls.OptionPositionsURL()
 	if accountNumber != nil {
 		url += "?account_numbers=" + *accountNumber
 	}
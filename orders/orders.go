@@ -3,12 +3,11 @@ package orders
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
 	"github.com/ikeboy003/robinstock-go/profiles"
 	"github.com/ikeboy003/robinstock-go/stocks"
 	"github.com/ikeboy003/robinstock-go/urls"
@@ -16,90 +15,81 @@ import (
 )
 
 // GetAllStockOrders returns all stock orders for an account.
-func GetAllStockOrders(ctx context.Context, client *robinstock_go.Client, accountNumber, startDate *string) ([]map[string]interface{}, error) {
-	log.Println("GetAllStockOrders: Fetching stock orders...")
+func GetAllStockOrders(ctx context.Context, client *robinstock_go.Client, accountNumber, startDate *string) ([]models.Order, error) {
+	client.Logger().DebugContext(ctx, "fetching stock orders", "op", "GetAllStockOrders")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
 	url := urls.OrdersURL(nil, accountNumber, startDate)
-	results, err := client.FetchAllPages(ctx, url, true)
+	orders, err := robinstock_go.FetchAllPagesTyped[models.Order](ctx, client, url, true)
 	if err != nil {
-		log.Printf("GetAllStockOrders: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetAllStockOrders", "error", err)
 		return nil, err
 	}
 
-	log.Printf("GetAllStockOrders: Retrieved %d orders\n", len(results))
-	return results, nil
+	client.Logger().DebugContext(ctx, "retrieved orders", "op", "GetAllStockOrders", "count", len(orders))
+	return orders, nil
 }
 
-// GetAllOpenStockOrders returns all open stock orders.
-func GetAllOpenStockOrders(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]map[string]interface{}, error) {
-	log.Println("GetAllOpenStockOrders: Fetching open stock orders...")
-
-	if !client.IsAuthenticated() {
-		return nil, robinstock_go.ErrNotAuthenticated
-	}
-
-	url := urls.OrdersURL(nil, accountNumber, nil)
-	results, err := client.FetchAllPages(ctx, url, true)
-	if err != nil {
-		log.Printf("GetAllOpenStockOrders: Error: %v\n", err)
-		return nil, err
-	}
-
-	var openOrders []map[string]interface{}
-	for _, order := range results {
-		if utils.GetString(order, "cancel") != "" {
-			openOrders = append(openOrders, order)
-		}
-	}
+// StockOrderPages returns a Pager over an account's stock orders, newest
+// first, fetching pages only as they are consumed. Decode pages with
+// robinstock_go.PageResults[models.Order].
+func StockOrderPages(client *robinstock_go.Client, accountNumber, startDate *string, opts ...robinstock_go.PagerOption) *robinstock_go.Pager {
+	return client.Pages(urls.OrdersURL(nil, accountNumber, startDate), true, opts...)
+}
 
-	log.Printf("GetAllOpenStockOrders: Retrieved %d open orders\n", len(openOrders))
-	return openOrders, nil
+// GetAllOpenStockOrders returns all open stock orders, judged by state. Only
+// orders created within the last 90 days are fetched, since older ones have
+// expired.
+func GetAllOpenStockOrders(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]models.Order, error) {
+	client.Logger().DebugContext(ctx, "fetching open stock orders", "op", "GetAllOpenStockOrders")
+	return GetStockOrders(ctx, client, openOrderFilter(accountNumber))
 }
 
 // GetStockOrderInfo returns information for a specific stock order.
-func GetStockOrderInfo(ctx context.Context, client *robinstock_go.Client, orderID string) (map[string]interface{}, error) {
-	log.Printf("GetStockOrderInfo: Fetching order %s...\n", orderID)
+func GetStockOrderInfo(ctx context.Context, client *robinstock_go.Client, orderID string) (*models.Order, error) {
+	client.Logger().DebugContext(ctx, "fetching order", "op", "GetStockOrderInfo", "order_id", orderID)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	url := urls.OrdersURL(&orderID, nil, nil)
-	resp, err := client.Get(ctx, url, nil, true)
+	order, err := robinstock_go.GetJSON[models.Order](ctx, client, urls.OrdersURL(&orderID, nil, nil), nil, true)
 	if err != nil {
-		log.Printf("GetStockOrderInfo: Error: %v\n", err)
+		client.Logger().ErrorContext(ctx, "request failed", "op", "GetStockOrderInfo", "error", err)
 		return nil, err
 	}
 
-	return resp.Data, nil
+	return order, nil
 }
 
-// CancelStockOrder cancels a specific stock order.
-func CancelStockOrder(ctx context.Context, client *robinstock_go.Client, orderID string) (map[string]interface{}, error) {
-	log.Printf("CancelStockOrder: Cancelling order %s...\n", orderID)
+// CancelStockOrder requests cancellation of a specific stock order and
+// returns the order as it stands afterwards. The cancel endpoint returns no
+// body, so the order is fetched again; its state may still be open until the
+// cancellation is processed.
+func CancelStockOrder(ctx context.Context, client *robinstock_go.Client, orderID string) (*models.Order, error) {
+	client.Logger().InfoContext(ctx, "cancelling order", "op", "CancelStockOrder", "order_id", orderID)
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
 	url := urls.CancelURL(orderID)
-	resp, err := client.Post(ctx, url, nil, true)
-	if err != nil {
-		log.Printf("CancelStockOrder: Error: %v\n", err)
+	if _, err := client.Post(ctx, url, nil, true); err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "CancelStockOrder", "error", err)
 		return nil, err
 	}
 
-	log.Printf("CancelStockOrder: Order %s cancelled\n", orderID)
-	return resp.Data, nil
+	client.Logger().InfoContext(ctx, "order cancelled", "op", "CancelStockOrder", "order_id", orderID)
+	return GetStockOrderInfo(ctx, client, orderID)
 }
 
-// CancelAllStockOrders cancels all open stock orders.
-func CancelAllStockOrders(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]map[string]interface{}, error) {
-	log.Println("CancelAllStockOrders: Cancelling all open stock orders...")
+// CancelAllStockOrders cancels all open stock orders and returns the orders
+// that were cancelled.
+func CancelAllStockOrders(ctx context.Context, client *robinstock_go.Client, accountNumber *string) ([]models.Order, error) {
+	client.Logger().InfoContext(ctx, "cancelling all open stock orders", "op", "CancelAllStockOrders")
 
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
@@ -110,78 +100,153 @@ func CancelAllStockOrders(ctx context.Context, client *robinstock_go.Client, acc
 		return nil, err
 	}
 
-	var cancelledOrders []map[string]interface{}
+	var cancelledOrders []models.Order
 	for _, order := range openOrders {
-		cancelURL := utils.GetString(order, "cancel")
-		if cancelURL != "" {
-			resp, err := client.Post(ctx, cancelURL, nil, true)
-			if err == nil {
-				cancelledOrders = append(cancelledOrders, resp.Data)
-			}
+		cancelURL := order.Cancel
+		if cancelURL == "" {
+			cancelURL = urls.CancelURL(order.ID)
 		}
+		if _, err := client.Post(ctx, cancelURL, nil, true); err != nil {
+			continue
+		}
+		if updated, err := GetStockOrderInfo(ctx, client, order.ID); err == nil {
+			order = *updated
+		}
+		cancelledOrders = append(cancelledOrders, order)
 	}
 
-	log.Printf("CancelAllStockOrders: Cancelled %d orders\n", len(cancelledOrders))
+	client.Logger().InfoContext(ctx, "cancelled orders", "op", "CancelAllStockOrders", "count", len(cancelledOrders))
 	return cancelledOrders, nil
 }
 
 // OrderBuyMarket submits a market buy order.
-func OrderBuyMarket(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderBuyMarket: Buying %f shares of %s...\n", quantity, symbol)
-	return placeOrder(ctx, client, symbol, quantity, "buy", nil, nil, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderBuyMarket(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideBuy,
+		Type:          models.TypeMarket,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderBuyLimit submits a limit buy order.
-func OrderBuyLimit(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, limitPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderBuyLimit: Buying %f shares of %s at limit $%f...\n", quantity, symbol, limitPrice)
-	return placeOrder(ctx, client, symbol, quantity, "buy", &limitPrice, nil, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderBuyLimit(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, limitPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideBuy,
+		Type:          models.TypeLimit,
+		Price:         &limitPrice,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderBuyStopLoss submits a stop loss buy order.
-func OrderBuyStopLoss(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, stopPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderBuyStopLoss: Buying %f shares of %s at stop $%f...\n", quantity, symbol, stopPrice)
-	return placeOrder(ctx, client, symbol, quantity, "buy", nil, &stopPrice, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderBuyStopLoss(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, stopPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideBuy,
+		Type:          models.TypeMarket,
+		StopPrice:     &stopPrice,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderBuyStopLimit submits a stop limit buy order.
-func OrderBuyStopLimit(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, limitPrice, stopPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderBuyStopLimit: Buying %f shares of %s at limit $%f, stop $%f...\n", quantity, symbol, limitPrice, stopPrice)
-	return placeOrder(ctx, client, symbol, quantity, "buy", &limitPrice, &stopPrice, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderBuyStopLimit(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, limitPrice, stopPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideBuy,
+		Type:          models.TypeLimit,
+		Price:         &limitPrice,
+		StopPrice:     &stopPrice,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderSellMarket submits a market sell order.
-func OrderSellMarket(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderSellMarket: Selling %f shares of %s...\n", quantity, symbol)
-	return placeOrder(ctx, client, symbol, quantity, "sell", nil, nil, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderSellMarket(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideSell,
+		Type:          models.TypeMarket,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderSellLimit submits a limit sell order.
-func OrderSellLimit(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, limitPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderSellLimit: Selling %f shares of %s at limit $%f...\n", quantity, symbol, limitPrice)
-	return placeOrder(ctx, client, symbol, quantity, "sell", &limitPrice, nil, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderSellLimit(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, limitPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideSell,
+		Type:          models.TypeLimit,
+		Price:         &limitPrice,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderSellStopLoss submits a stop loss sell order.
-func OrderSellStopLoss(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, stopPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderSellStopLoss: Selling %f shares of %s at stop $%f...\n", quantity, symbol, stopPrice)
-	return placeOrder(ctx, client, symbol, quantity, "sell", nil, &stopPrice, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderSellStopLoss(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, stopPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideSell,
+		Type:          models.TypeMarket,
+		StopPrice:     &stopPrice,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderSellStopLimit submits a stop limit sell order.
-func OrderSellStopLimit(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, limitPrice, stopPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderSellStopLimit: Selling %f shares of %s at limit $%f, stop $%f...\n", quantity, symbol, limitPrice, stopPrice)
-	return placeOrder(ctx, client, symbol, quantity, "sell", &limitPrice, &stopPrice, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderSellStopLimit(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, limitPrice, stopPrice float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideSell,
+		Type:          models.TypeLimit,
+		Price:         &limitPrice,
+		StopPrice:     &stopPrice,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderBuyFractionalByQuantity submits a fractional share buy order by quantity.
-func OrderBuyFractionalByQuantity(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderBuyFractionalByQuantity: Buying %f fractional shares of %s...\n", quantity, symbol)
-	return placeOrder(ctx, client, symbol, quantity, "buy", nil, nil, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderBuyFractionalByQuantity(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideBuy,
+		Type:          models.TypeMarket,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderBuyFractionalByPrice submits a fractional share buy order by dollar amount.
-func OrderBuyFractionalByPrice(ctx context.Context, client *robinstock_go.Client, symbol string, amountInDollars float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderBuyFractionalByPrice: Buying $%f worth of %s...\n", amountInDollars, symbol)
+func OrderBuyFractionalByPrice(ctx context.Context, client *robinstock_go.Client, symbol string, amountInDollars float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	client.Logger().InfoContext(ctx, "sizing fractional order", "op", "OrderBuyFractionalByPrice", "symbol", symbol, "amount", amountInDollars)
 
 	if amountInDollars < 1 {
 		return nil, fmt.Errorf("fractional share price should meet minimum $1.00")
@@ -199,18 +264,33 @@ func OrderBuyFractionalByPrice(ctx context.Context, client *robinstock_go.Client
 	}
 
 	fractionalShares := utils.RoundPrice(amountInDollars / price)
-	return placeOrder(ctx, client, symbol, fractionalShares, "buy", nil, nil, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      fractionalShares,
+		Side:          models.SideBuy,
+		Type:          models.TypeMarket,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderSellFractionalByQuantity submits a fractional share sell order by quantity.
-func OrderSellFractionalByQuantity(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderSellFractionalByQuantity: Selling %f fractional shares of %s...\n", quantity, symbol)
-	return placeOrder(ctx, client, symbol, quantity, "sell", nil, nil, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+func OrderSellFractionalByQuantity(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.SideSell,
+		Type:          models.TypeMarket,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderSellFractionalByPrice submits a fractional share sell order by dollar amount.
-func OrderSellFractionalByPrice(ctx context.Context, client *robinstock_go.Client, symbol string, amountInDollars float64, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderSellFractionalByPrice: Selling $%f worth of %s...\n", amountInDollars, symbol)
+func OrderSellFractionalByPrice(ctx context.Context, client *robinstock_go.Client, symbol string, amountInDollars float64, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	client.Logger().InfoContext(ctx, "sizing fractional order", "op", "OrderSellFractionalByPrice", "symbol", symbol, "amount", amountInDollars)
 
 	if amountInDollars < 1 {
 		return nil, fmt.Errorf("fractional share price should meet minimum $1.00")
@@ -228,21 +308,92 @@ func OrderSellFractionalByPrice(ctx context.Context, client *robinstock_go.Clien
 	}
 
 	fractionalShares := utils.RoundPrice(amountInDollars / price)
-	return placeOrder(ctx, client, symbol, fractionalShares, "sell", nil, nil, accountNumber, timeInForce, extendedHours, "regular_hours", nil, "")
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      fractionalShares,
+		Side:          models.SideSell,
+		Type:          models.TypeMarket,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+	})
 }
 
 // OrderTrailingStop submits a trailing stop order.
-func OrderTrailingStop(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, side string, trailAmount float64, trailType string, accountNumber *string, timeInForce string, extendedHours bool) (map[string]interface{}, error) {
-	log.Printf("OrderTrailingStop: %s %f shares of %s with trail %f (%s)...\n", side, quantity, symbol, trailAmount, trailType)
-	return placeOrder(ctx, client, symbol, quantity, side, nil, nil, accountNumber, timeInForce, extendedHours, "regular_hours", &trailAmount, trailType)
+func OrderTrailingStop(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, side string, trailAmount float64, trailType string, accountNumber *string, timeInForce string, extendedHours bool) (*models.Order, error) {
+	return Submit(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.OrderSide(side),
+		Type:          models.TypeMarket,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+		TrailAmount:   &trailAmount,
+		TrailType:     models.TrailType(trailType),
+	})
+}
+
+// PreviewStockOrder builds the payload placeOrder would submit for the same
+// arguments, resolving the account, instrument and prices, without sending
+// it. It is a positional form of Preview.
+func PreviewStockOrder(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, side string, limitPrice, stopPrice *float64, accountNumber *string, timeInForce string, extendedHours bool, marketHours string, trailAmount *float64, trailType string) (*models.OrderPreview, error) {
+	return Preview(ctx, client, models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Side:          models.OrderSide(side),
+		Price:         limitPrice,
+		StopPrice:     stopPrice,
+		TimeInForce:   models.TimeInForce(timeInForce),
+		ExtendedHours: extendedHours,
+		AccountNumber: accountNumber,
+		MarketHours:   models.MarketSession(marketHours),
+		TrailAmount:   trailAmount,
+		TrailType:     models.TrailType(trailType),
+	})
+}
+
+// placeOrder builds and posts the order described by a normalized req.
+func placeOrder(ctx context.Context, client *robinstock_go.Client, req models.OrderRequest) (*models.Order, error) {
+	preview, err := buildStockOrder(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+	logWarnings(ctx, client, "placeOrder", preview)
+
+	order, err := robinstock_go.PostJSON[models.Order](ctx, client, preview.URL, preview.Payload, true)
+	if err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "placeOrder", "error", err)
+		return nil, err
+	}
+
+	client.Logger().InfoContext(ctx, "order placed", "op", "placeOrder", "symbol", req.Symbol, "order_id", order.ID)
+	return order, nil
 }
 
-func placeOrder(ctx context.Context, client *robinstock_go.Client, symbol string, quantity float64, side string, limitPrice, stopPrice *float64, accountNumber *string, timeInForce string, extendedHours bool, marketHours string, trailAmount *float64, trailType string) (map[string]interface{}, error) {
+// buildStockOrder resolves a normalized req into the order payload.
+func buildStockOrder(ctx context.Context, client *robinstock_go.Client, req models.OrderRequest) (*models.OrderPreview, error) {
 	if !client.IsAuthenticated() {
 		return nil, robinstock_go.ErrNotAuthenticated
 	}
 
-	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	symbol := req.Symbol
+	quantity := req.Quantity
+	side := string(req.Side)
+	stopPrice := req.StopPrice
+	accountNumber := req.AccountNumber
+	timeInForce := string(req.TimeInForce)
+	extendedHours := req.ExtendedHours
+	marketHours := string(req.MarketHours)
+	trailAmount := req.TrailAmount
+	trailType := string(req.TrailType)
+
+	var limitPrice *float64
+	if req.Type == models.TypeLimit {
+		limitPrice = req.Price
+	}
+
+	var warnings []string
 
 	orderType := "market"
 	trigger := "immediate"
@@ -255,26 +406,35 @@ func placeOrder(ctx context.Context, client *robinstock_go.Client, symbol string
 	}
 
 	var price float64
-	if limitPrice != nil && stopPrice != nil {
+	var stop *float64
+	if stopPrice != nil {
+		rounded := utils.RoundPrice(*stopPrice)
+		stop = &rounded
+	}
+	if limitPrice != nil && stop != nil {
 		price = utils.RoundPrice(*limitPrice)
-		*stopPrice = utils.RoundPrice(*stopPrice)
 		orderType = "limit"
 		trigger = "stop"
 	} else if limitPrice != nil {
 		price = utils.RoundPrice(*limitPrice)
 		orderType = "limit"
-	} else if stopPrice != nil {
-		*stopPrice = utils.RoundPrice(*stopPrice)
+	} else if stop != nil {
 		if side == "buy" {
-			price = *stopPrice
+			price = *stop
 		}
 		trigger = "stop"
 	} else {
-		prices, err := stocks.GetLatestPrice(ctx, client, &priceType, extendedHours, symbol)
-		if err != nil || len(prices) == 0 {
+		latest, err := latestPrice(ctx, client, &priceType, extendedHours, symbol)
+		if err != nil {
 			return nil, fmt.Errorf("failed to get latest price: %w", err)
 		}
-		price = utils.RoundPrice(utils.ParseFloat(prices[0]))
+		price = utils.RoundPrice(latest)
+	}
+	if limitPrice != nil && price != *limitPrice {
+		warnings = append(warnings, fmt.Sprintf("limit price %v rounded to %.2f", *limitPrice, price))
+	}
+	if stopPrice != nil && *stop != *stopPrice {
+		warnings = append(warnings, fmt.Sprintf("stop price %v rounded to %.2f", *stopPrice, *stop))
 	}
 
 	accountURL, err := getAccountURL(ctx, client, accountNumber)
@@ -287,17 +447,11 @@ func placeOrder(ctx context.Context, client *robinstock_go.Client, symbol string
 		return nil, err
 	}
 
-	askPrices, _ := stocks.GetLatestPrice(ctx, client, utils.Address("ask_price"), extendedHours, symbol)
-	bidPrices, _ := stocks.GetLatestPrice(ctx, client, utils.Address("bid_price"), extendedHours, symbol)
-
 	payload := map[string]interface{}{
 		"account":            accountURL,
 		"instrument":         instrumentURL,
 		"symbol":             symbol,
 		"price":              price,
-		"ask_price":          utils.RoundPrice(utils.ParseFloat(askPrices[0])),
-		"bid_price":          utils.RoundPrice(utils.ParseFloat(bidPrices[0])),
-		"bid_ask_timestamp":  time.Now().Format("2006-01-02 15:04:05.000000"),
 		"quantity":           quantity,
 		"ref_id":             uuid.NewString(),
 		"type":               orderType,
@@ -309,8 +463,18 @@ func placeOrder(ctx context.Context, client *robinstock_go.Client, symbol string
 		"order_form_version": 4,
 	}
 
-	if stopPrice != nil {
-		payload["stop_price"] = *stopPrice
+	askPrice, askErr := latestPrice(ctx, client, utils.Address("ask_price"), extendedHours, symbol)
+	bidPrice, bidErr := latestPrice(ctx, client, utils.Address("bid_price"), extendedHours, symbol)
+	if askErr == nil && bidErr == nil {
+		payload["ask_price"] = utils.RoundPrice(askPrice)
+		payload["bid_price"] = utils.RoundPrice(bidPrice)
+		payload["bid_ask_timestamp"] = time.Now().Format("2006-01-02 15:04:05.000000")
+	} else {
+		warnings = append(warnings, "bid/ask quote unavailable; order sent without bid_price, ask_price and bid_ask_timestamp")
+	}
+
+	if stop != nil {
+		payload["stop_price"] = *stop
 	}
 
 	if orderType == "market" && trigger != "stop" {
@@ -321,17 +485,29 @@ func placeOrder(ctx context.Context, client *robinstock_go.Client, symbol string
 		if side == "buy" {
 			payload["preset_percent_limit"] = "0.05"
 			payload["type"] = "limit"
+			if orderType == "market" {
+				warnings = append(warnings, "market buy sent as a limit order with a 5% collar (preset_percent_limit)")
+			}
 		} else if orderType == "market" && side == "sell" {
 			delete(payload, "price")
 		}
 	} else if marketHours == "extended_hours" || marketHours == "all_day_hours" {
 		payload["type"] = "limit"
 		payload["quantity"] = int(quantity)
+		if orderType == "market" {
+			warnings = append(warnings, fmt.Sprintf("market order sent as a limit order for %s", marketHours))
+		}
+		if float64(int(quantity)) != quantity {
+			warnings = append(warnings, fmt.Sprintf("quantity %v truncated to %d for %s", quantity, int(quantity), marketHours))
+		}
 	}
 
 	if trailAmount != nil {
-		stockPrices, _ := stocks.GetLatestPrice(ctx, client, nil, extendedHours, symbol)
-		stockPrice := utils.RoundPrice(utils.ParseFloat(stockPrices[0]))
+		latest, err := latestPrice(ctx, client, nil, extendedHours, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest price: %w", err)
+		}
+		stockPrice := utils.RoundPrice(latest)
 
 		var margin float64
 		var percentage float64
@@ -354,6 +530,7 @@ func placeOrder(ctx context.Context, client *robinstock_go.Client, symbol string
 		payload["stop_price"] = calculatedStopPrice
 		payload["type"] = "market"
 		payload["trigger"] = "stop"
+		warnings = append(warnings, fmt.Sprintf("trailing stop starts at %.2f from last price %.2f", calculatedStopPrice, stockPrice))
 
 		if side == "buy" {
 			payload["price"] = utils.RoundPrice(calculatedStopPrice * 1.05)
@@ -375,15 +552,34 @@ func placeOrder(ctx context.Context, client *robinstock_go.Client, symbol string
 		}
 	}
 
-	url := urls.OrdersURL(nil, accountNumber, nil)
-	resp, err := client.Post(ctx, url, payload, true)
+	return &models.OrderPreview{
+		URL:      urls.OrdersURL(nil, accountNumber, nil),
+		Payload:  payload,
+		Warnings: warnings,
+	}, nil
+}
+
+// latestPrice returns the latest price of priceType for symbol, or an error
+// when no usable price is available.
+func latestPrice(ctx context.Context, client *robinstock_go.Client, priceType *string, extendedHours bool, symbol string) (float64, error) {
+	prices, err := stocks.GetLatestPrice(ctx, client, priceType, extendedHours, symbol)
 	if err != nil {
-		log.Printf("placeOrder: Error: %v\n", err)
-		return nil, err
+		return 0, err
+	}
+	if len(prices) == 0 {
+		return 0, fmt.Errorf("no quote for %s", symbol)
 	}
+	price := utils.ParseFloat(prices[0])
+	if price == 0 {
+		return 0, fmt.Errorf("no price for %s", symbol)
+	}
+	return price, nil
+}
 
-	log.Printf("placeOrder: Order placed successfully. Order ID: %s\n", utils.GetString(resp.Data, "id"))
-	return resp.Data, nil
+func logWarnings(ctx context.Context, client *robinstock_go.Client, op string, preview *models.OrderPreview) {
+	for _, warning := range preview.Warnings {
+		client.Logger().InfoContext(ctx, "order adjusted", "op", op, "warning", warning)
+	}
 }
 
 func getAccountURL(ctx context.Context, client *robinstock_go.Client, accountNumber *string) (string, error) {
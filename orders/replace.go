@@ -0,0 +1,338 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
+	"github.com/ikeboy003/robinstock-go/stocks"
+	"github.com/ikeboy003/robinstock-go/urls"
+	"github.com/ikeboy003/robinstock-go/utils"
+)
+
+// cancelConfirmTimeout bounds the wait for a cancellation to land during a
+// replace.
+const cancelConfirmTimeout = 30 * time.Second
+
+var (
+	// ErrOrderNotOpen is returned when replacing an order that has already
+	// reached a terminal state.
+	ErrOrderNotOpen = errors.New("order is not open")
+	// ErrCancelNotConfirmed is returned when a replaced order was not
+	// confirmed cancelled in time. No replacement is placed, and the order
+	// may still be working.
+	ErrCancelNotConfirmed = errors.New("order cancellation not confirmed")
+	// ErrNothingToReplace is returned when the order filled, or filled past
+	// the new quantity, before it was cancelled. No replacement is placed.
+	ErrNothingToReplace = errors.New("nothing left to replace")
+	// ErrReplacementFailed is returned when the original order was cancelled
+	// but its replacement could not be placed, leaving neither working.
+	ErrReplacementFailed = errors.New("order cancelled but replacement not placed")
+)
+
+// Replace modifies an open stock order by cancelling it and placing a new
+// one. Robinhood has no amend, so the replacement is only submitted once the
+// cancellation is confirmed, and only for the quantity that did not fill in
+// the meantime; the two orders can never both be working.
+//
+// Trailing stops are replaced as trailing stops with the same trail, so
+// changes cannot set their price or stop price. Regular-hours buys are
+// recorded as collared limit orders even when submitted at market; replacing
+// one needs changes.Price, making the replacement a limit order.
+//
+// The replacement is validated and built, resolving its account,
+// instrument and quote, before the cancel is sent; once the cancel is
+// confirmed only its quantity changes. Extended- and all-day-hours orders
+// must be for whole shares so their remainder stays valid. It returns the cancelled order as it ended and the
+// replacement. When the order is cancelled but no replacement is placed, the
+// cancelled order is returned along with ErrNothingToReplace or
+// ErrReplacementFailed.
+func Replace(ctx context.Context, client *robinstock_go.Client, orderID string, changes models.OrderChanges) (cancelled, replacement *models.Order, err error) {
+	client.Logger().InfoContext(ctx, "replacing order", "op", "Replace", "order_id", orderID)
+
+	if !client.IsAuthenticated() {
+		return nil, nil, robinstock_go.ErrNotAuthenticated
+	}
+
+	original, err := GetStockOrderInfo(ctx, client, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !original.State.IsOpen() {
+		return nil, nil, fmt.Errorf("%w: %s is %s", ErrOrderNotOpen, orderID, original.State)
+	}
+
+	req, err := replacementRequest(ctx, client, original, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	preview, err := buildStockOrder(ctx, client, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := client.Post(ctx, urls.CancelURL(orderID), nil, true); err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "Replace", "error", err)
+		return nil, nil, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, cancelConfirmTimeout)
+	defer cancel()
+	cancelled, err = awaitTerminal(waitCtx, Watch(waitCtx, client, orderID, WithPollInterval(250*time.Millisecond), WithMaxPollInterval(2*time.Second)))
+	if err != nil {
+		return cancelled, nil, err
+	}
+
+	remaining := req.Quantity - cancelled.CumulativeQuantity
+	if cancelled.State != models.StateCancelled || remaining <= 0 {
+		return cancelled, nil, fmt.Errorf("%w: %s is %s with %v filled", ErrNothingToReplace, orderID, cancelled.State, cancelled.CumulativeQuantity)
+	}
+	setRemainder(preview, remaining)
+	logWarnings(ctx, client, "Replace", preview)
+
+	replacement, err = robinstock_go.PostJSON[models.Order](ctx, client, preview.URL, preview.Payload, true)
+	if err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "Replace", "error", err)
+		return cancelled, nil, fmt.Errorf("%w: %s: %w", ErrReplacementFailed, orderID, err)
+	}
+
+	client.Logger().InfoContext(ctx, "order replaced", "op", "Replace", "order_id", orderID, "replacement_id", replacement.ID, "quantity", remaining)
+	return cancelled, replacement, nil
+}
+
+// replacementRequest describes original with changes applied. Quantity is
+// the new total, before subtracting any fills. Trailing stops keep their
+// trail; a collared regular-hours buy is rejected unless changes sets Price.
+func replacementRequest(ctx context.Context, client *robinstock_go.Client, original *models.Order, changes models.OrderChanges) (models.OrderRequest, error) {
+	symbol := original.Symbol
+	if symbol == "" {
+		var err error
+		symbol, err = stocks.GetSymbolByURL(ctx, client, original.Instrument)
+		if err != nil {
+			return models.OrderRequest{}, fmt.Errorf("resolve symbol: %w", err)
+		}
+	}
+
+	req := models.OrderRequest{
+		Symbol:        symbol,
+		Quantity:      original.Quantity,
+		Side:          original.Side,
+		Type:          original.Type,
+		TimeInForce:   original.TimeInForce,
+		ExtendedHours: original.ExtendedHours,
+		MarketHours:   original.MarketHours,
+	}
+	if original.Account != "" {
+		accountNumber := path.Base(strings.TrimSuffix(original.Account, "/"))
+		req.AccountNumber = &accountNumber
+	}
+	switch {
+	case original.TrailingPeg != nil:
+		// A trailing stop is recorded as a stop at its latest level; carry
+		// the peg over instead so the replacement keeps trailing.
+		amount := original.TrailingPeg.Amount
+		req.Type = models.TypeMarket
+		req.TrailAmount = &amount
+		req.TrailType = original.TrailingPeg.Type
+	case original.Type == models.TypeLimit && original.PresetPercentLimit > 0 && changes.Price == nil:
+		// Regular-hours buys are all placed as collared limits, so the record
+		// cannot tell a market buy from a limit at the same price.
+		return models.OrderRequest{}, fmt.Errorf("%w: %s may have been a market buy placed as a collared limit; set Price to replace it as a limit", ErrInvalidOrder, original.ID)
+	default:
+		if original.Type == models.TypeLimit {
+			price := original.Price
+			req.Price = &price
+		}
+		if original.Trigger == models.TriggerStop {
+			stopPrice := original.StopPrice
+			req.StopPrice = &stopPrice
+		}
+	}
+
+	if changes.Price != nil {
+		req.Type = models.TypeLimit
+		req.Price = changes.Price
+	}
+	if changes.StopPrice != nil {
+		req.StopPrice = changes.StopPrice
+	}
+	if changes.Quantity != nil {
+		req.Quantity = *changes.Quantity
+	}
+	if changes.TimeInForce != "" {
+		req.TimeInForce = changes.TimeInForce
+	}
+
+	// Outside regular hours only whole shares trade, so a whole quantity
+	// leaves a whole remainder that can always be resubmitted.
+	if req.MarketHours != "" && req.MarketHours != models.MarketHoursRegular && req.Quantity != math.Trunc(req.Quantity) {
+		return req, fmt.Errorf("%w: %s orders need a whole number of shares to be replaced, got %v", ErrInvalidOrder, req.MarketHours, req.Quantity)
+	}
+
+	return normalizeOrderRequest(req)
+}
+
+// ReplaceOptionOrder is Replace for option orders. The replacement keeps the
+// original's legs and direction and is likewise built before the cancel;
+// Quantity is in contracts.
+func ReplaceOptionOrder(ctx context.Context, client *robinstock_go.Client, orderID string, changes models.OrderChanges) (cancelled, replacement *models.OptionOrder, err error) {
+	client.Logger().InfoContext(ctx, "replacing option order", "op", "ReplaceOptionOrder", "order_id", orderID)
+
+	if !client.IsAuthenticated() {
+		return nil, nil, robinstock_go.ErrNotAuthenticated
+	}
+
+	original, err := GetOptionOrderInfo(ctx, client, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !original.State.IsOpen() {
+		return nil, nil, fmt.Errorf("%w: %s is %s", ErrOrderNotOpen, orderID, original.State)
+	}
+	if len(original.Legs) == 0 {
+		return nil, nil, fmt.Errorf("option order %s has no legs", orderID)
+	}
+
+	quantity := original.Quantity
+	if changes.Quantity != nil {
+		quantity = *changes.Quantity
+	}
+	if quantity <= 0 || quantity != math.Trunc(quantity) {
+		return nil, nil, fmt.Errorf("%w: quantity must be a positive whole number of contracts, got %v", ErrInvalidOrder, quantity)
+	}
+	preview, err := buildOptionReplacement(ctx, client, original, changes, int(quantity))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := client.Post(ctx, urls.OptionCancelURL(orderID), nil, true); err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "ReplaceOptionOrder", "error", err)
+		return nil, nil, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, cancelConfirmTimeout)
+	defer cancel()
+	cancelled, err = awaitTerminal(waitCtx, WatchOptionOrder(waitCtx, client, orderID, WithPollInterval(250*time.Millisecond), WithMaxPollInterval(2*time.Second)))
+	if err != nil {
+		return cancelled, nil, err
+	}
+
+	remaining := quantity - cancelled.ProcessedQuantity
+	if cancelled.State != models.StateCancelled || remaining <= 0 {
+		return cancelled, nil, fmt.Errorf("%w: %s is %s with %v filled", ErrNothingToReplace, orderID, cancelled.State, cancelled.ProcessedQuantity)
+	}
+
+	setRemainder(preview, remaining)
+	logWarnings(ctx, client, "ReplaceOptionOrder", preview)
+
+	replacement, err = robinstock_go.PostJSON[models.OptionOrder](ctx, client, preview.URL, preview.Payload, true)
+	if err != nil {
+		client.Logger().ErrorContext(ctx, "request failed", "op", "ReplaceOptionOrder", "error", err)
+		return cancelled, nil, fmt.Errorf("%w: %s: %w", ErrReplacementFailed, orderID, err)
+	}
+
+	client.Logger().InfoContext(ctx, "order replaced", "op", "ReplaceOptionOrder", "order_id", orderID, "replacement_id", replacement.ID, "quantity", remaining)
+	return cancelled, replacement, nil
+}
+
+// buildOptionReplacement builds an order for quantity contracts with the
+// same legs as original and changes applied.
+func buildOptionReplacement(ctx context.Context, client *robinstock_go.Client, original *models.OptionOrder, changes models.OrderChanges, quantity int) (*models.OrderPreview, error) {
+	var accountNumber *string
+	if original.AccountNumber != "" {
+		accountNumber = &original.AccountNumber
+	}
+	accountURL, err := getAccountURL(ctx, client, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	price := original.Price
+	if changes.Price != nil {
+		price = *changes.Price
+	}
+	stopPrice := 0.0
+	if original.Trigger == models.TriggerStop {
+		stopPrice = original.StopPrice
+	}
+	if changes.StopPrice != nil {
+		stopPrice = *changes.StopPrice
+	}
+	timeInForce := original.TimeInForce
+	if changes.TimeInForce != "" {
+		timeInForce = changes.TimeInForce
+	}
+
+	var legs []map[string]interface{}
+	for _, leg := range original.Legs {
+		legs = append(legs, map[string]interface{}{
+			"position_effect": leg.PositionEffect,
+			"side":            string(leg.Side),
+			"ratio_quantity":  leg.RatioQuantity,
+			"option":          leg.Option,
+		})
+	}
+
+	payload := map[string]interface{}{
+		"account":                   accountURL,
+		"direction":                 original.Direction,
+		"time_in_force":             string(timeInForce),
+		"legs":                      legs,
+		"type":                      "limit",
+		"trigger":                   "immediate",
+		"price":                     utils.RoundPrice(price),
+		"quantity":                  quantity,
+		"override_day_trade_checks": false,
+		"override_dtbp_checks":      false,
+		"ref_id":                    uuid.NewString(),
+	}
+	if stopPrice > 0 {
+		payload["trigger"] = "stop"
+		payload["stop_price"] = utils.RoundPrice(stopPrice)
+	}
+
+	return &models.OrderPreview{
+		URL:      urls.OptionOrdersURL(nil, accountNumber, nil),
+		Payload:  payload,
+		Warnings: priceRoundingWarnings(price, stopPrice),
+	}, nil
+}
+
+// setRemainder sets the quantity of a replacement built before the cancel to
+// what was left unfilled once the cancel was confirmed. Whole quantities,
+// as sent outside regular hours and for options, stay whole.
+func setRemainder(preview *models.OrderPreview, remaining float64) {
+	if _, whole := preview.Payload["quantity"].(int); whole {
+		preview.Payload["quantity"] = int(remaining)
+		return
+	}
+	// Fractional shares go to six decimal places; drop the float error
+	// left by the subtraction.
+	preview.Payload["quantity"] = math.Round(remaining*1e6) / 1e6
+}
+
+// awaitTerminal drains events until the watch ends and returns the order in
+// its final state. The watch retries transient poll failures until ctx ends
+// and stops on any other, which is returned.
+func awaitTerminal[T any](ctx context.Context, events <-chan WatchEvent[T]) (T, error) {
+	var last WatchEvent[T]
+	var lastErr error
+	for event := range events {
+		if event.Err != nil {
+			lastErr = event.Err
+			continue
+		}
+		last = event
+	}
+	if !last.State.IsTerminal() {
+		return last.Order, fmt.Errorf("%w: %w", ErrCancelNotConfirmed, errors.Join(ctx.Err(), lastErr))
+	}
+	return last.Order, nil
+}
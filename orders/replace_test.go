@@ -0,0 +1,246 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+const (
+	openStockOrder  = `{"id":"o1","state":"confirmed","type":"limit","side":"sell","price":"10.00","quantity":"10","cumulative_quantity":"0","time_in_force":"gfd","trigger":"immediate","market_hours":"regular_hours","account":"https://api.robinhood.com/accounts/ACC/","instrument":"https://api.robinhood.com/instruments/i1/"}`
+	openOptionOrder = `{"id":"x1","state":"confirmed","account_number":"ACC","direction":"debit","price":"1.00","quantity":"3","processed_quantity":"0","time_in_force":"gfd","trigger":"immediate","legs":[{"option":"https://api.robinhood.com/options/instruments/c1/","side":"buy","position_effect":"open","ratio_quantity":1}]}`
+)
+
+// broker fakes the endpoints a replace touches. The order at orderPath is
+// served as open until its cancel is posted and as afterCancel from then on,
+// or as a 404 when afterCancel is empty.
+// Requests to failPath get a 404 and replacement submits get submitStatus
+// when it is set.
+type broker struct {
+	orderPath    string
+	open         string
+	afterCancel  string
+	failPath     string
+	submitStatus int
+
+	mu        sync.Mutex
+	cancelled bool
+	requests  []string
+	submitted map[string]interface{}
+}
+
+func (b *broker) start(t *testing.T) *robinstock_go.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.requests = append(b.requests, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.URL.Path == b.failPath:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"Not found."}`))
+		case r.Method == http.MethodPost && r.URL.Path == b.orderPath+"cancel/":
+			b.cancelled = true
+			w.Write([]byte(`{}`))
+		case r.URL.Path == b.orderPath:
+			if b.cancelled && b.afterCancel == "" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"detail":"Not found."}`))
+				return
+			}
+			if b.cancelled {
+				w.Write([]byte(b.afterCancel))
+				return
+			}
+			w.Write([]byte(b.open))
+		case r.Method == http.MethodPost && (r.URL.Path == "/orders/" || r.URL.Path == "/options/orders/"):
+			json.NewDecoder(r.Body).Decode(&b.submitted)
+			if b.submitStatus != 0 {
+				w.WriteHeader(b.submitStatus)
+				w.Write([]byte(`{"detail":"Order rejected."}`))
+				return
+			}
+			w.Write([]byte(`{"id":"new","state":"unconfirmed"}`))
+		case r.URL.Path == "/accounts/ACC/":
+			w.Write([]byte(`{"url":"https://api.robinhood.com/accounts/ACC/","account_number":"ACC"}`))
+		case r.URL.Path == "/instruments/i1/":
+			w.Write([]byte(`{"id":"i1","symbol":"AAPL","url":"https://api.robinhood.com/instruments/i1/"}`))
+		case r.URL.Path == "/instruments/":
+			w.Write([]byte(`{"results":[{"id":"i1","symbol":"AAPL","url":"https://api.robinhood.com/instruments/i1/"}]}`))
+		case r.URL.Path == "/quotes/":
+			w.Write([]byte(`{"results":[{"symbol":"AAPL","ask_price":"10.05","bid_price":"10.00"}]}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return testClient(robinstock_go.WithHosts(robinstock_go.Hosts{API: srv.URL}))
+}
+
+// sent reports whether a request was made and its position among all
+// requests, so tests can check the cancel went out before the submit.
+func (b *broker) sent(request string) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i := slices.Index(b.requests, request)
+	return i, i >= 0
+}
+
+func TestReplacePartialFillBeforeCancel(t *testing.T) {
+	b := &broker{
+		orderPath:   "/orders/o1/",
+		open:        openStockOrder,
+		afterCancel: strings.NewReplacer(`"confirmed"`, `"cancelled"`, `"cumulative_quantity":"0"`, `"cumulative_quantity":"3"`).Replace(openStockOrder),
+	}
+	client := b.start(t)
+
+	price := 9.5
+	cancelled, replacement, err := Replace(context.Background(), client, "o1", models.OrderChanges{Price: &price})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled.State != models.StateCancelled || replacement.ID != "new" {
+		t.Errorf("cancelled %s, replacement %q", cancelled.State, replacement.ID)
+	}
+	if b.submitted["quantity"] != 7.0 || b.submitted["price"] != 9.5 || b.submitted["time_in_force"] != "gfd" {
+		t.Errorf("submitted %v, want 7 at 9.5 gfd", b.submitted)
+	}
+	cancel, _ := b.sent("POST /orders/o1/cancel/")
+	submit, _ := b.sent("POST /orders/")
+	lookup, _ := b.sent("GET /instruments/")
+	if !(lookup < cancel && cancel < submit) {
+		t.Errorf("requests %q, want lookups, then the cancel, then the submit", b.requests)
+	}
+}
+
+func TestReplaceCancelNotConfirmed(t *testing.T) {
+	t.Run("still working", func(t *testing.T) {
+		b := &broker{orderPath: "/orders/o1/", open: openStockOrder, afterCancel: openStockOrder}
+		client := b.start(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 600*time.Millisecond)
+		defer cancel()
+		price := 9.5
+		_, _, err := Replace(ctx, client, "o1", models.OrderChanges{Price: &price})
+		if !errors.Is(err, ErrCancelNotConfirmed) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err = %v, want ErrCancelNotConfirmed after the deadline", err)
+		}
+		if _, ok := b.sent("POST /orders/"); ok {
+			t.Error("submitted a replacement while the original may be working")
+		}
+	})
+
+	t.Run("order gone", func(t *testing.T) {
+		b := &broker{orderPath: "/orders/o1/", open: openStockOrder}
+		client := b.start(t)
+
+		price := 9.5
+		_, _, err := Replace(context.Background(), client, "o1", models.OrderChanges{Price: &price})
+		if !errors.Is(err, ErrCancelNotConfirmed) || !errors.Is(err, robinstock_go.ErrNotFound) {
+			t.Errorf("err = %v, want ErrCancelNotConfirmed with the 404", err)
+		}
+		if _, ok := b.sent("POST /orders/"); ok {
+			t.Error("submitted a replacement for an order that was never confirmed cancelled")
+		}
+	})
+}
+
+func TestReplaceSubmitFails(t *testing.T) {
+	b := &broker{
+		orderPath:    "/orders/o1/",
+		open:         openStockOrder,
+		afterCancel:  strings.Replace(openStockOrder, `"confirmed"`, `"cancelled"`, 1),
+		submitStatus: http.StatusBadRequest,
+	}
+	client := b.start(t)
+
+	price := 9.5
+	cancelled, replacement, err := Replace(context.Background(), client, "o1", models.OrderChanges{Price: &price})
+	if !errors.Is(err, ErrReplacementFailed) {
+		t.Fatalf("err = %v, want ErrReplacementFailed", err)
+	}
+	var apiErr *robinstock_go.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("err = %v, want the submit's 400 wrapped", err)
+	}
+	if cancelled == nil || cancelled.State != models.StateCancelled || replacement != nil {
+		t.Errorf("cancelled %+v, replacement %+v, want the cancelled order and no replacement", cancelled, replacement)
+	}
+}
+
+func TestReplaceLookupFailsBeforeCancel(t *testing.T) {
+	for _, failPath := range []string{"/instruments/", "/accounts/ACC/", "/quotes/"} {
+		t.Run(failPath, func(t *testing.T) {
+			b := &broker{orderPath: "/orders/o1/", open: openStockOrder, failPath: failPath}
+			if failPath == "/quotes/" {
+				// A limit sell only needs the quote for its bid/ask fields,
+				// so make the lookup essential with a market order.
+				b.open = strings.Replace(openStockOrder, `"type":"limit"`, `"type":"market"`, 1)
+			}
+			client := b.start(t)
+
+			_, _, err := Replace(context.Background(), client, "o1", models.OrderChanges{})
+			if err == nil || errors.Is(err, ErrReplacementFailed) {
+				t.Errorf("err = %v, want the lookup failure", err)
+			}
+			if _, ok := b.sent("POST /orders/o1/cancel/"); ok {
+				t.Error("cancelled the order although the replacement could not be built")
+			}
+		})
+	}
+}
+
+func TestReplaceOptionOrderPartialFill(t *testing.T) {
+	b := &broker{
+		orderPath:   "/options/orders/x1/",
+		open:        openOptionOrder,
+		afterCancel: strings.NewReplacer(`"confirmed"`, `"cancelled"`, `"processed_quantity":"0"`, `"processed_quantity":"1"`).Replace(openOptionOrder),
+	}
+	client := b.start(t)
+
+	price := 1.25
+	_, replacement, err := ReplaceOptionOrder(context.Background(), client, "x1", models.OrderChanges{Price: &price})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replacement.ID != "new" || b.submitted["quantity"] != 2.0 || b.submitted["price"] != 1.25 {
+		t.Errorf("submitted %v, want 2 contracts at 1.25", b.submitted)
+	}
+}
+
+func TestReplaceOptionOrderLookupFailsBeforeCancel(t *testing.T) {
+	b := &broker{orderPath: "/options/orders/x1/", open: openOptionOrder, failPath: "/accounts/ACC/"}
+	client := b.start(t)
+
+	if _, _, err := ReplaceOptionOrder(context.Background(), client, "x1", models.OrderChanges{}); err == nil {
+		t.Fatal("want the account lookup failure")
+	}
+	if _, ok := b.sent("POST /options/orders/x1/cancel/"); ok {
+		t.Error("cancelled the order although the replacement could not be built")
+	}
+}
+
+func TestSetRemainder(t *testing.T) {
+	fractional := &models.OrderPreview{Payload: map[string]interface{}{"quantity": 10.5}}
+	setRemainder(fractional, 10.5-3.3)
+	if fractional.Payload["quantity"] != 7.2 {
+		t.Errorf("fractional remainder = %v, want 7.2", fractional.Payload["quantity"])
+	}
+	whole := &models.OrderPreview{Payload: map[string]interface{}{"quantity": 10}}
+	setRemainder(whole, 4)
+	if whole.Payload["quantity"] != 4 {
+		t.Errorf("whole remainder = %#v, want int 4", whole.Payload["quantity"])
+	}
+}
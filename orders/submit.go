@@ -0,0 +1,109 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// ErrInvalidOrder is returned when an OrderRequest fails validation. No
+// request is sent.
+var ErrInvalidOrder = errors.New("invalid order")
+
+// Submit validates req and places the stock order it describes.
+func Submit(ctx context.Context, client *robinstock_go.Client, req models.OrderRequest) (*models.Order, error) {
+	req, err := normalizeOrderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	client.Logger().InfoContext(ctx, "submitting order", "op", "Submit", "symbol", req.Symbol, "side", req.Side, "type", req.Type, "quantity", req.Quantity)
+	return placeOrder(ctx, client, req)
+}
+
+// Preview validates req and builds the payload Submit would send, without
+// sending it.
+func Preview(ctx context.Context, client *robinstock_go.Client, req models.OrderRequest) (*models.OrderPreview, error) {
+	req, err := normalizeOrderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	client.Logger().DebugContext(ctx, "previewing order", "op", "Preview", "symbol", req.Symbol, "side", req.Side, "type", req.Type, "quantity", req.Quantity)
+	return buildStockOrder(ctx, client, req)
+}
+
+// normalizeOrderRequest fills in defaults and checks that req describes an
+// order Robinhood accepts.
+func normalizeOrderRequest(req models.OrderRequest) (models.OrderRequest, error) {
+	invalid := func(format string, args ...interface{}) (models.OrderRequest, error) {
+		return req, fmt.Errorf("%w: %s", ErrInvalidOrder, fmt.Sprintf(format, args...))
+	}
+
+	req.Symbol = strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if req.Symbol == "" {
+		return invalid("symbol is required")
+	}
+	if req.Quantity <= 0 {
+		return invalid("quantity must be positive, got %v", req.Quantity)
+	}
+	if !req.Side.Valid() {
+		return invalid("side %q", req.Side)
+	}
+
+	if req.Type == "" {
+		req.Type = models.TypeMarket
+		if req.Price != nil {
+			req.Type = models.TypeLimit
+		}
+	}
+	if !req.Type.Valid() {
+		return invalid("type %q", req.Type)
+	}
+	if req.Type == models.TypeLimit && (req.Price == nil || *req.Price <= 0) {
+		return invalid("limit order requires a positive price")
+	}
+	if req.Type == models.TypeMarket && req.Price != nil {
+		return invalid("market order cannot have a price")
+	}
+	if req.StopPrice != nil && *req.StopPrice <= 0 {
+		return invalid("stop price must be positive, got %v", *req.StopPrice)
+	}
+
+	if req.TimeInForce == "" {
+		req.TimeInForce = models.TIFGTC
+	}
+	if !req.TimeInForce.Valid() {
+		return invalid("time in force %q", req.TimeInForce)
+	}
+
+	if req.MarketHours == "" {
+		req.MarketHours = models.MarketHoursRegular
+	}
+	if !req.MarketHours.Valid() {
+		return invalid("market hours %q", req.MarketHours)
+	}
+	if req.MarketHours != models.MarketHoursRegular && req.Quantity < 1 {
+		return invalid("%s orders need at least one whole share", req.MarketHours)
+	}
+
+	if req.TrailAmount != nil {
+		if *req.TrailAmount <= 0 {
+			return invalid("trail amount must be positive, got %v", *req.TrailAmount)
+		}
+		if !req.TrailType.Valid() {
+			return invalid("trail type %q", req.TrailType)
+		}
+		if req.Type != models.TypeMarket || req.StopPrice != nil {
+			return invalid("trailing stop cannot have a price or stop price")
+		}
+	} else if req.TrailType != "" {
+		return invalid("trail type set without trail amount")
+	}
+
+	return req, nil
+}
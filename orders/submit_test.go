@@ -0,0 +1,106 @@
+package orders
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+func TestNormalizeOrderRequestDefaults(t *testing.T) {
+	price := 10.0
+	tests := []struct {
+		name string
+		req  models.OrderRequest
+		want models.OrderRequest
+	}{
+		{
+			name: "market",
+			req:  models.OrderRequest{Symbol: " aapl ", Quantity: 1, Side: models.SideBuy},
+			want: models.OrderRequest{Symbol: "AAPL", Quantity: 1, Side: models.SideBuy, Type: models.TypeMarket, TimeInForce: models.TIFGTC, MarketHours: models.MarketHoursRegular},
+		},
+		{
+			name: "price implies limit",
+			req:  models.OrderRequest{Symbol: "AAPL", Quantity: 2, Side: models.SideSell, Price: &price, TimeInForce: models.TIFGFD},
+			want: models.OrderRequest{Symbol: "AAPL", Quantity: 2, Side: models.SideSell, Type: models.TypeLimit, Price: &price, TimeInForce: models.TIFGFD, MarketHours: models.MarketHoursRegular},
+		},
+		{
+			name: "extended hours kept",
+			req:  models.OrderRequest{Symbol: "AAPL", Quantity: 1, Side: models.SideBuy, Price: &price, MarketHours: models.MarketHoursExtended},
+			want: models.OrderRequest{Symbol: "AAPL", Quantity: 1, Side: models.SideBuy, Type: models.TypeLimit, Price: &price, TimeInForce: models.TIFGTC, MarketHours: models.MarketHoursExtended},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeOrderRequest(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeOrderRequestTrailingStop(t *testing.T) {
+	trail := 5.0
+	got, err := normalizeOrderRequest(models.OrderRequest{Symbol: "AAPL", Quantity: 1, Side: models.SideSell, TrailAmount: &trail, TrailType: models.TrailTypePercentage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != models.TypeMarket || got.Price != nil || got.StopPrice != nil {
+		t.Errorf("trailing stop normalized to %+v", got)
+	}
+}
+
+func TestNormalizeOrderRequestRejects(t *testing.T) {
+	price, zero, negative := 10.0, 0.0, -1.0
+	valid := func(modify func(*models.OrderRequest)) models.OrderRequest {
+		req := models.OrderRequest{Symbol: "AAPL", Quantity: 1, Side: models.SideBuy}
+		modify(&req)
+		return req
+	}
+	tests := []struct {
+		name string
+		req  models.OrderRequest
+	}{
+		{"empty symbol", valid(func(r *models.OrderRequest) { r.Symbol = "  " })},
+		{"zero quantity", valid(func(r *models.OrderRequest) { r.Quantity = 0 })},
+		{"negative quantity", valid(func(r *models.OrderRequest) { r.Quantity = -2 })},
+		{"missing side", valid(func(r *models.OrderRequest) { r.Side = "" })},
+		{"unknown side", valid(func(r *models.OrderRequest) { r.Side = "short" })},
+		{"unknown type", valid(func(r *models.OrderRequest) { r.Type = "stop" })},
+		{"limit without price", valid(func(r *models.OrderRequest) { r.Type = models.TypeLimit })},
+		{"limit with zero price", valid(func(r *models.OrderRequest) { r.Type = models.TypeLimit; r.Price = &zero })},
+		{"market with price", valid(func(r *models.OrderRequest) { r.Type = models.TypeMarket; r.Price = &price })},
+		{"negative stop", valid(func(r *models.OrderRequest) { r.StopPrice = &negative })},
+		{"unknown time in force", valid(func(r *models.OrderRequest) { r.TimeInForce = "fok" })},
+		{"unknown market hours", valid(func(r *models.OrderRequest) { r.MarketHours = "overnight" })},
+		{"fractional extended hours", valid(func(r *models.OrderRequest) {
+			r.MarketHours = models.MarketHoursExtended
+			r.Quantity = 0.5
+			r.Price = &price
+		})},
+		{"zero trail", valid(func(r *models.OrderRequest) { r.TrailAmount = &zero; r.TrailType = models.TrailTypeAmount })},
+		{"trail without type", valid(func(r *models.OrderRequest) { r.TrailAmount = &price })},
+		{"trail type without amount", valid(func(r *models.OrderRequest) { r.TrailType = models.TrailTypeAmount })},
+		{"trail with price", valid(func(r *models.OrderRequest) {
+			r.TrailAmount = &price
+			r.TrailType = models.TrailTypeAmount
+			r.Price = &price
+		})},
+		{"trail with stop", valid(func(r *models.OrderRequest) {
+			r.TrailAmount = &price
+			r.TrailType = models.TrailTypeAmount
+			r.StopPrice = &price
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := normalizeOrderRequest(tt.req); !errors.Is(err, ErrInvalidOrder) {
+				t.Errorf("err = %v, want ErrInvalidOrder", err)
+			}
+		})
+	}
+}
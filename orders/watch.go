@@ -0,0 +1,238 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultMaxPollInterval = 30 * time.Second
+)
+
+// WatchOption configures Watch and WatchOptionOrder.
+type WatchOption func(*watchConfig)
+
+type watchConfig struct {
+	minInterval time.Duration
+	maxInterval time.Duration
+}
+
+// WithPollInterval sets the delay between polls while the order is changing.
+// Default 1s.
+func WithPollInterval(d time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		if d > 0 {
+			c.minInterval = d
+		}
+	}
+}
+
+// WithMaxPollInterval caps the delay between polls once the order stops
+// changing. Default 30s.
+func WithMaxPollInterval(d time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		if d > 0 {
+			c.maxInterval = d
+		}
+	}
+}
+
+func newWatchConfig(opts []WatchOption) watchConfig {
+	c := watchConfig{minInterval: defaultPollInterval, maxInterval: defaultMaxPollInterval}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.maxInterval < c.minInterval {
+		c.maxInterval = c.minInterval
+	}
+	return c
+}
+
+// WatchEvent reports a change in a watched order: its state, its filled
+// quantity, or new executions.
+type WatchEvent[T any] struct {
+	// Order is the order as just fetched. It is nil when Err is set.
+	Order T
+	State models.OrderState
+	// PreviousState is the state reported by the previous event, empty on
+	// the first.
+	PreviousState models.OrderState
+	// FilledQuantity is the cumulative quantity filled so far.
+	FilledQuantity float64
+	// NewExecutions are the executions not reported by an earlier event.
+	NewExecutions []models.Execution
+	// Err is set when a poll failed; the other fields repeat the last known
+	// state. Watching carries on after network errors, 5xx and 429
+	// responses and stops after this event otherwise.
+	Err error
+}
+
+// OrderEvent is a change in a stock order watched with Watch.
+type OrderEvent = WatchEvent[*models.Order]
+
+// OptionOrderEvent is a change in an option order watched with
+// WatchOptionOrder.
+type OptionOrderEvent = WatchEvent[*models.OptionOrder]
+
+// Watch polls a stock order and streams an event for its current state and
+// then for every change, until the order reaches a terminal state or ctx is
+// done. The channel is closed when watching stops. A poll that fails with a
+// network error, a 5xx or a 429 is reported as an event with Err set and
+// polling carries on, so bound the watch with ctx. Any other failure, such
+// as an unknown order or auth that cannot be refreshed, is reported as a
+// final event with Err set before the channel is closed.
+//
+// Polling starts at the WithPollInterval delay and backs off towards
+// WithMaxPollInterval while the order is unchanged or polls fail, dropping
+// back after each change.
+//
+//	for event := range orders.Watch(ctx, client, order.ID) {
+//		if event.Err != nil {
+//			log.Println("poll failed:", event.Err)
+//			continue
+//		}
+//		log.Println(event.State, event.FilledQuantity)
+//	}
+func Watch(ctx context.Context, client *robinstock_go.Client, orderID string, opts ...WatchOption) <-chan OrderEvent {
+	fetch := func(ctx context.Context) (*models.Order, error) {
+		return GetStockOrderInfo(ctx, client, orderID)
+	}
+	status := func(o *models.Order) (models.OrderState, float64, []models.Execution) {
+		return o.State, o.CumulativeQuantity, o.Executions
+	}
+	return watch(ctx, client, "Watch", orderID, newWatchConfig(opts), fetch, status)
+}
+
+// WatchOptionOrder is Watch for option orders. FilledQuantity is in
+// contracts and NewExecutions covers every leg.
+func WatchOptionOrder(ctx context.Context, client *robinstock_go.Client, orderID string, opts ...WatchOption) <-chan OptionOrderEvent {
+	fetch := func(ctx context.Context) (*models.OptionOrder, error) {
+		return GetOptionOrderInfo(ctx, client, orderID)
+	}
+	status := func(o *models.OptionOrder) (models.OrderState, float64, []models.Execution) {
+		var executions []models.Execution
+		for _, leg := range o.Legs {
+			executions = append(executions, leg.Executions...)
+		}
+		return o.State, o.ProcessedQuantity, executions
+	}
+	return watch(ctx, client, "WatchOptionOrder", orderID, newWatchConfig(opts), fetch, status)
+}
+
+func watch[T any](ctx context.Context, client *robinstock_go.Client, op, orderID string, cfg watchConfig, fetch func(context.Context) (T, error), status func(T) (models.OrderState, float64, []models.Execution)) <-chan WatchEvent[T] {
+	events := make(chan WatchEvent[T])
+
+	go func() {
+		defer close(events)
+
+		send := func(event WatchEvent[T]) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		sleep := func(d time.Duration) bool {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var (
+			state    models.OrderState
+			filled   float64
+			reported bool
+			seen     = make(map[string]bool)
+			interval = cfg.minInterval
+		)
+
+		for {
+			order, err := fetch(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				client.Logger().WarnContext(ctx, "order poll failed", "op", op, "order_id", orderID, "error", err)
+				if !send(WatchEvent[T]{State: state, PreviousState: state, FilledQuantity: filled, Err: err}) || !transient(err) {
+					return
+				}
+				interval = min(interval*2, cfg.maxInterval)
+				if !sleep(interval) {
+					return
+				}
+				continue
+			}
+
+			current, currentFilled, executions := status(order)
+			var fresh []models.Execution
+			for _, execution := range executions {
+				key := execution.ID
+				if key == "" {
+					key = execution.Timestamp.String()
+				}
+				if !seen[key] {
+					seen[key] = true
+					fresh = append(fresh, execution)
+				}
+			}
+
+			if !reported || current != state || currentFilled != filled || len(fresh) > 0 {
+				client.Logger().DebugContext(ctx, "order changed", "op", op, "order_id", orderID, "state", current, "filled", currentFilled)
+				event := WatchEvent[T]{
+					Order:          order,
+					State:          current,
+					PreviousState:  state,
+					FilledQuantity: currentFilled,
+					NewExecutions:  fresh,
+				}
+				if !send(event) {
+					return
+				}
+				state, filled, reported = current, currentFilled, true
+				interval = cfg.minInterval
+			} else {
+				interval = min(interval*2, cfg.maxInterval)
+			}
+
+			if current.IsTerminal() {
+				return
+			}
+
+			if !sleep(interval) {
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// transient reports whether a failed poll may succeed if repeated: network
+// errors, 5xx responses and rate limiting. Anything else, such as an
+// unknown order, missing auth or a 401 that could not be refreshed, fails
+// the same way on every poll.
+func transient(err error) bool {
+	var apiErr *robinstock_go.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,139 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// orderServer answers each poll of a stock order with the next response in
+// turn, repeating the last one, and counts the polls.
+func orderServer(t *testing.T, responses ...func(http.ResponseWriter)) (*httptest.Server, *int32) {
+	t.Helper()
+	var polls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&polls, 1)) - 1
+		responses[min(i, len(responses)-1)](w)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &polls
+}
+
+func respond(status int, body string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+func collectEvents(t *testing.T, client *robinstock_go.Client, orderID string) []OrderEvent {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var events []OrderEvent
+	for event := range Watch(ctx, client, orderID, WithPollInterval(time.Millisecond), WithMaxPollInterval(4*time.Millisecond)) {
+		events = append(events, event)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("watch did not stop before the deadline")
+	}
+	return events
+}
+
+func TestWatchRetriesTransientErrors(t *testing.T) {
+	srv, _ := orderServer(t,
+		respond(http.StatusOK, `{"id":"o1","state":"confirmed"}`),
+		respond(http.StatusServiceUnavailable, ``),
+		respond(http.StatusTooManyRequests, ``),
+		respond(http.StatusOK, `{"id":"o1","state":"filled","cumulative_quantity":"1"}`),
+	)
+	events := collectEvents(t, testClient(robinstock_go.WithHosts(robinstock_go.Hosts{API: srv.URL})), "o1")
+
+	var errs int
+	for _, event := range events {
+		if event.Err != nil {
+			errs++
+		}
+	}
+	if errs != 2 {
+		t.Errorf("got %d error events, want 2", errs)
+	}
+	if last := events[len(events)-1]; last.State != models.StateFilled || last.PreviousState != models.StateConfirmed {
+		t.Errorf("last event %s -> %s, want confirmed -> filled", last.PreviousState, last.State)
+	}
+}
+
+func TestWatchStopsOnPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{"unknown order", http.StatusNotFound, robinstock_go.ErrNotFound},
+		{"unauthorized", http.StatusUnauthorized, robinstock_go.ErrUnauthorized},
+		{"bad request", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, polls := orderServer(t,
+				respond(http.StatusOK, `{"id":"o1","state":"queued"}`),
+				respond(tt.status, `{"detail":"nope"}`),
+			)
+			events := collectEvents(t, testClient(robinstock_go.WithHosts(robinstock_go.Hosts{API: srv.URL})), "o1")
+
+			if len(events) != 2 {
+				t.Fatalf("got %d events, want the first state and one error", len(events))
+			}
+			last := events[1]
+			var apiErr *robinstock_go.APIError
+			if !errors.As(last.Err, &apiErr) || apiErr.StatusCode != tt.status || (tt.want != nil && !errors.Is(last.Err, tt.want)) {
+				t.Errorf("err = %v, want a %d", last.Err, tt.status)
+			}
+			if last.State != models.StateQueued {
+				t.Errorf("error event state = %q, want the last known state", last.State)
+			}
+			if n := atomic.LoadInt32(polls); n != 2 {
+				t.Errorf("polled %d times, want no polls after the error", n)
+			}
+		})
+	}
+}
+
+func TestWatchStopsWhenNotAuthenticated(t *testing.T) {
+	client := robinstock_go.NewClient()
+	events := collectEvents(t, client, "o1")
+	if len(events) != 1 || !errors.Is(events[0].Err, robinstock_go.ErrNotAuthenticated) {
+		t.Errorf("events = %+v, want a single ErrNotAuthenticated", events)
+	}
+}
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"server error", &robinstock_go.APIError{StatusCode: http.StatusBadGateway}, true},
+		{"rate limited", &robinstock_go.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"not found", &robinstock_go.APIError{StatusCode: http.StatusNotFound}, false},
+		{"unauthorized", &robinstock_go.APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"network", &url.Error{Op: "Get", URL: "https://api.robinhood.com/", Err: errors.New("connection reset")}, true},
+		{"not authenticated", robinstock_go.ErrNotAuthenticated, false},
+		{"no refresh token", robinstock_go.ErrNoRefreshToken, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transient(tt.err); got != tt.want {
+				t.Errorf("transient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package robinstock_go
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// PagerOption configures a Pager.
+type PagerOption func(*Pager)
+
+// WithPageSize asks the API for at most n results per page.
+func WithPageSize(n int) PagerOption {
+	return func(p *Pager) {
+		if n > 0 {
+			p.pageSize = n
+		}
+	}
+}
+
+// WithMaxPages stops the Pager after n pages have been fetched.
+func WithMaxPages(n int) PagerOption {
+	return func(p *Pager) {
+		if n > 0 {
+			p.maxPages = n
+		}
+	}
+}
+
+// Pager iterates over a paginated response one page at a time, following
+// next links only when Next is called. Callers may stop iterating at any
+// point without fetching the remaining pages:
+//
+//	p := client.Pages(url, true, robinstock_go.WithPageSize(50))
+//	for p.Next(ctx) {
+//		for _, result := range p.Results() {
+//			...
+//		}
+//	}
+//	if err := p.Err(); err != nil {
+//		...
+//	}
+type Pager struct {
+	client        *Client
+	nextURL       string
+	authenticated bool
+	pageSize      int
+	maxPages      int
+	pages         int
+	resp          *models.Response
+	err           error
+}
+
+// Pages returns a Pager over the paginated response starting at initialURL.
+// No request is made until Next is called.
+func (c *Client) Pages(initialURL string, authenticated bool, opts ...PagerOption) *Pager {
+	p := &Pager{
+		client:        c,
+		nextURL:       initialURL,
+		authenticated: authenticated,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.pageSize > 0 && p.nextURL != "" {
+		p.nextURL = withQueryParam(p.nextURL, "page_size", strconv.Itoa(p.pageSize))
+	}
+	return p
+}
+
+// withQueryParam sets key=value on urlStr, keeping any existing query.
+func withQueryParam(urlStr, key, value string) string {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return urlStr
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Next fetches the next page. It returns false when there are no more pages,
+// the page limit was reached, ctx was cancelled or a request failed; Err
+// distinguishes the last two cases.
+func (p *Pager) Next(ctx context.Context) bool {
+	if p.err != nil || p.nextURL == "" {
+		return false
+	}
+	if p.maxPages > 0 && p.pages >= p.maxPages {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+
+	resp, err := p.client.Get(ctx, p.nextURL, nil, p.authenticated)
+	if err != nil {
+		p.err = err
+		p.resp = nil
+		return false
+	}
+
+	p.resp = resp
+	p.pages++
+	p.nextURL = ""
+	if next, ok := resp.Data["next"].(string); ok {
+		p.nextURL = next
+	}
+	return true
+}
+
+// Response returns the current page's response.
+func (p *Pager) Response() *models.Response {
+	return p.resp
+}
+
+// Results returns the current page's results.
+func (p *Pager) Results() []map[string]interface{} {
+	if p.resp == nil {
+		return nil
+	}
+	return p.resp.Results
+}
+
+// HasMore reports whether the API advertised another page after the current
+// one.
+func (p *Pager) HasMore() bool {
+	return p.nextURL != ""
+}
+
+// Err returns the error that stopped iteration, if any.
+func (p *Pager) Err() error {
+	return p.err
+}
+
+// PageResults unmarshals the Pager's current page results into a []T.
+func PageResults[T any](p *Pager) ([]T, error) {
+	if p.resp == nil {
+		return nil, nil
+	}
+	page, err := DecodeResponse[Page[T]](p.resp)
+	if err != nil {
+		return nil, err
+	}
+	return page.Results, nil
+}
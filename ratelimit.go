@@ -0,0 +1,162 @@
+package robinstock_go
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a token bucket that refills at Rate requests per
+// second and holds at most Burst tokens.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitStats reports how much the client-side rate limiter has delayed
+// outgoing requests.
+type RateLimitStats struct {
+	Requests   int64
+	Delayed    int64
+	TotalDelay time.Duration
+	MaxDelay   time.Duration
+}
+
+// WithRateLimit applies limit to every request the client sends.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.limiter.global = newTokenBucket(limit)
+	}
+}
+
+// WithHostRateLimit applies limit to requests sent to host, in addition to
+// any global limit. host may be a bare host name or a base URL such as one
+// of the fields of Hosts.
+func WithHostRateLimit(host string, limit RateLimit) Option {
+	return func(c *Client) {
+		if c.limiter.hosts == nil {
+			c.limiter.hosts = make(map[string]*tokenBucket)
+		}
+		c.limiter.hosts[hostKey(host)] = newTokenBucket(limit)
+	}
+}
+
+// RateLimitStats returns a snapshot of the limiter's delay metrics.
+func (c *Client) RateLimitStats() RateLimitStats {
+	c.limiter.mu.Lock()
+	defer c.limiter.mu.Unlock()
+	return c.limiter.stats
+}
+
+type rateLimiter struct {
+	global *tokenBucket
+	hosts  map[string]*tokenBucket
+
+	mu    sync.Mutex
+	stats RateLimitStats
+}
+
+// wait blocks until both the global and the per-host bucket for urlStr allow
+// a request, or until ctx is done.
+func (l *rateLimiter) wait(ctx context.Context, urlStr string) error {
+	start := time.Now()
+	buckets := []*tokenBucket{l.global}
+	if u, err := url.Parse(urlStr); err == nil {
+		buckets = append(buckets, l.hosts[strings.ToLower(u.Hostname())])
+	}
+
+	for _, b := range buckets {
+		if err := b.wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	delay := time.Since(start)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stats.Requests++
+	if delay >= time.Millisecond {
+		l.stats.Delayed++
+		l.stats.TotalDelay += delay
+		if delay > l.stats.MaxDelay {
+			l.stats.MaxDelay = delay
+		}
+	}
+	return nil
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token, sleeping until one is available. If ctx ends first
+// the token is returned to the bucket.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok && delay > 0 && time.Until(deadline) < delay {
+		b.refund()
+		return context.DeadlineExceeded
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		b.refund()
+		return err
+	}
+	return nil
+}
+
+func (b *tokenBucket) refund() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func hostKey(host string) string {
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil {
+			return strings.ToLower(u.Hostname())
+		}
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "/"))
+}
@@ -0,0 +1,113 @@
+package robinstock_go
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurstThenThrottle(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 20, Burst: 3})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := b.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Fatalf("burst of 3 took %v, want no wait", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 2; i++ {
+		if err := b.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Two tokens at 20/s take about 100ms to refill.
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("2 requests past the burst took %v, want about 100ms", elapsed)
+	}
+}
+
+func TestTokenBucketCancelWhileWaiting(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 1, Burst: 1})
+	if err := b.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if err := b.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("wait returned after %v, want promptly on cancel", elapsed)
+	}
+
+	// The cancelled wait gave its token back, so the bucket is no deeper
+	// in debt than after the first request.
+	b.mu.Lock()
+	tokens := b.tokens
+	b.mu.Unlock()
+	if tokens < -0.1 {
+		t.Fatalf("tokens = %v after cancelled wait, want refunded", tokens)
+	}
+}
+
+func TestTokenBucketDeadlineTooSoon(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 0.1, Burst: 1})
+	b.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := b.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("wait took %v, want immediate failure when the deadline is too close", elapsed)
+	}
+}
+
+func TestClientRateLimitStats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithRateLimit(RateLimit{Rate: 50, Burst: 1}))
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(context.Background(), srv.URL+"/quotes/", nil, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats := c.RateLimitStats()
+	if stats.Requests != 3 || stats.Delayed != 2 || stats.MaxDelay <= 0 {
+		t.Fatalf("stats = %+v, want 3 requests with 2 delayed", stats)
+	}
+}
+
+func TestHostRateLimitOnlyAppliesToHost(t *testing.T) {
+	c := NewClient(WithHostRateLimit("http://limited.example", RateLimit{Rate: 1, Burst: 1}))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 5; i++ {
+		if err := c.limiter.wait(ctx, "http://other.example/quotes/"); err != nil {
+			t.Fatalf("unlimited host: %v", err)
+		}
+	}
+	if err := c.limiter.wait(ctx, "http://limited.example/quotes/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.limiter.wait(ctx, "http://LIMITED.example/quotes/"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second request to limited host: err = %v, want context.DeadlineExceeded", err)
+	}
+}
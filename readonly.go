@@ -0,0 +1,64 @@
+package robinstock_go
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrReadOnlyClient is returned, before anything is sent, for requests a
+// read-only client refuses.
+var ErrReadOnlyClient = errors.New("client is read-only")
+
+// readOnlyPOST is a POST endpoint that neither trades nor moves money.
+type readOnlyPOST struct {
+	host   func(Hosts) string
+	prefix string
+}
+
+// readOnlyPOSTs are allowed on a read-only client so it can still log in,
+// refresh its token, complete verification and run screener scans.
+var readOnlyPOSTs = []readOnlyPOST{
+	{func(h Hosts) string { return h.API }, "/oauth2/token/"},
+	{func(h Hosts) string { return h.API }, "/challenge/"},
+	{func(h Hosts) string { return h.API }, "/pathfinder/"},
+	{func(h Hosts) string { return h.API }, "/push/"},
+	{func(h Hosts) string { return h.Bonfire }, "/screeners/scan/"},
+}
+
+// WithReadOnly makes the client refuse every request that could place or
+// cancel orders or move money, returning ErrReadOnlyClient. Only GET, HEAD
+// and OPTIONS requests and the POSTs needed to authenticate and run screener
+// scans are sent.
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.readOnly = true
+	}
+}
+
+// ReadOnly reports whether the client was created with WithReadOnly.
+func (c *Client) ReadOnly() bool {
+	return c.readOnly
+}
+
+// checkReadOnly returns ErrReadOnlyClient if req is not allowed on a
+// read-only client. req.URL must already be resolved.
+func (c *Client) checkReadOnly(req *Request) error {
+	if !c.readOnly {
+		return nil
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	case http.MethodPost:
+		for _, allowed := range readOnlyPOSTs {
+			base := allowed.host(c.hosts)
+			if hasHostPrefix(req.URL, base) && strings.HasPrefix(req.URL[len(base):], allowed.prefix) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s %s", ErrReadOnlyClient, req.Method, req.URL)
+}
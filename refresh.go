@@ -0,0 +1,143 @@
+package robinstock_go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go/models"
+	"github.com/ikeboy003/robinstock-go/urls"
+	"github.com/ikeboy003/robinstock-go/utils"
+)
+
+const defaultRefreshWindow = 5 * time.Minute
+
+// ErrNoRefreshToken is returned by RefreshAuth when the client's credentials
+// carry no refresh token.
+var ErrNoRefreshToken = errors.New("no refresh token")
+
+// TokenRefreshHandler is called after the client refreshes its access token,
+// so the new credentials can be persisted.
+type TokenRefreshHandler func(ctx context.Context, auth *models.Auth)
+
+// tokenRefresher holds the automatic refresh settings. mu serializes
+// refreshes so concurrent requests that see an expired token share one.
+type tokenRefresher struct {
+	mu        sync.Mutex
+	disabled  bool
+	window    time.Duration
+	onRefresh TokenRefreshHandler
+}
+
+// WithAutoRefresh enables or disables automatic token refresh. It is enabled
+// by default: authenticated requests refresh the access token shortly before
+// it expires, and once more after a 401, replaying the original request.
+func WithAutoRefresh(enabled bool) Option {
+	return func(c *Client) {
+		c.refresher.disabled = !enabled
+	}
+}
+
+// WithRefreshWindow sets how long before expiry the access token is
+// refreshed proactively. The default is five minutes.
+func WithRefreshWindow(d time.Duration) Option {
+	return func(c *Client) {
+		if d >= 0 {
+			c.refresher.window = d
+		}
+	}
+}
+
+// WithTokenRefreshHandler sets a callback invoked with the new credentials
+// after every successful refresh.
+func WithTokenRefreshHandler(fn TokenRefreshHandler) Option {
+	return func(c *Client) {
+		c.refresher.onRefresh = fn
+	}
+}
+
+// RefreshAuth exchanges the client's refresh token for a new access token
+// and installs it.
+func (c *Client) RefreshAuth(ctx context.Context) (*models.Auth, error) {
+	if err := c.refreshAuth(ctx, c.GetAuth()); err != nil {
+		return nil, err
+	}
+	return c.GetAuth(), nil
+}
+
+// canRefresh reports whether auth can be refreshed automatically.
+func (c *Client) canRefresh(auth *models.Auth) bool {
+	return !c.refresher.disabled && auth != nil && auth.RefreshToken != ""
+}
+
+// refreshIfExpiring refreshes the access token when it is about to expire.
+// A failed refresh is logged and the request proceeds with the old token.
+func (c *Client) refreshIfExpiring(ctx context.Context) {
+	auth := c.GetAuth()
+	if !c.canRefresh(auth) || !auth.ExpiresWithin(c.refresher.window) {
+		return
+	}
+	if err := c.refreshAuth(ctx, auth); err != nil {
+		c.logger.WarnContext(ctx, "proactive token refresh failed", "error", err)
+	}
+}
+
+// refreshAuth replaces stale with freshly refreshed credentials. If another
+// goroutine already replaced stale while this one waited, it returns
+// without refreshing again.
+func (c *Client) refreshAuth(ctx context.Context, stale *models.Auth) error {
+	if stale == nil || stale.RefreshToken == "" {
+		return ErrNoRefreshToken
+	}
+
+	c.refresher.mu.Lock()
+	defer c.refresher.mu.Unlock()
+
+	if c.GetAuth() != stale {
+		return nil
+	}
+
+	payload := map[string]string{
+		"client_id":     models.ClientID,
+		"grant_type":    "refresh_token",
+		"refresh_token": stale.RefreshToken,
+		"device_token":  stale.DeviceToken,
+		"scope":         "internal",
+	}
+	resp, err := c.Do(ctx, &Request{
+		Method: http.MethodPost,
+		URL:    urls.LoginURL(),
+		Body:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("refresh token: %w", err)
+	}
+
+	auth := &models.Auth{
+		AccessToken:  utils.GetString(resp.Data, "access_token"),
+		RefreshToken: utils.GetString(resp.Data, "refresh_token"),
+		TokenType:    utils.GetString(resp.Data, "token_type"),
+		DeviceToken:  stale.DeviceToken,
+		ExpiresIn:    utils.GetInt(resp.Data, "expires_in"),
+		IssuedAt:     time.Now(),
+	}
+	if auth.AccessToken == "" {
+		return fmt.Errorf("refresh token: no access token in response")
+	}
+	if auth.RefreshToken == "" {
+		auth.RefreshToken = stale.RefreshToken
+	}
+	if auth.TokenType == "" {
+		auth.TokenType = stale.TokenType
+	}
+
+	c.SetAuth(auth)
+	c.logger.InfoContext(ctx, "access token refreshed", "expires_at", auth.ExpiresAt())
+	if c.refresher.onRefresh != nil {
+		c.refresher.onRefresh(ctx, auth)
+	}
+	return nil
+}
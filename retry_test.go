@@ -0,0 +1,164 @@
+package robinstock_go
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetry = RetryPolicy{
+	MaxAttempts:   3,
+	BaseDelay:     time.Millisecond,
+	MaxDelay:      5 * time.Millisecond,
+	RetryStatuses: DefaultRetryPolicy().RetryStatuses,
+}
+
+// failingServer answers the first failures requests with status and every
+// later one with 200.
+func failingServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRetryGet(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusTooManyRequests} {
+		srv, calls := failingServer(t, 2, status, nil)
+		c := NewClient(WithRetryPolicy(fastRetry))
+		if _, err := c.Get(context.Background(), srv.URL+"/quotes/", nil, false); err != nil {
+			t.Fatalf("status %d: %v", status, err)
+		}
+		if got := calls.Load(); got != 3 {
+			t.Errorf("status %d: attempts = %d, want 3", status, got)
+		}
+	}
+}
+
+func TestRetryGetGivesUp(t *testing.T) {
+	srv, calls := failingServer(t, 10, http.StatusServiceUnavailable, nil)
+	c := NewClient(WithRetryPolicy(fastRetry))
+	_, err := c.Get(context.Background(), srv.URL+"/quotes/", nil, false)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want 503 APIError", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRetryGetNotOnClientError(t *testing.T) {
+	srv, calls := failingServer(t, 1, http.StatusBadRequest, nil)
+	c := NewClient(WithRetryPolicy(fastRetry))
+	if _, err := c.Get(context.Background(), srv.URL+"/quotes/", nil, false); err == nil {
+		t.Fatal("want error for 400")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryPostNeedsRefID(t *testing.T) {
+	tests := []struct {
+		name string
+		body interface{}
+		want int32
+	}{
+		{"ref_id", map[string]interface{}{"ref_id": "abc", "quantity": 1}, 3},
+		{"no ref_id", map[string]interface{}{"quantity": 1}, 1},
+		{"empty ref_id", map[string]interface{}{"ref_id": ""}, 1},
+		{"null ref_id", map[string]interface{}{"ref_id": nil}, 1},
+		{"no body", nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := failingServer(t, 2, http.StatusServiceUnavailable, nil)
+			c := NewClient(WithRetryPolicy(fastRetry))
+			c.Post(context.Background(), srv.URL+"/orders/", tt.body, false)
+			if got := calls.Load(); got != tt.want {
+				t.Errorf("attempts = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv, calls := failingServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	c := NewClient(WithRetryPolicy(fastRetry))
+	start := time.Now()
+	if _, err := c.Get(context.Background(), srv.URL+"/quotes/", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter("3"); !ok || d != 3*time.Second {
+		t.Errorf("seconds: got %v, %v", d, ok)
+	}
+	if d, ok := parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)); !ok || d != 0 {
+		t.Errorf("past date: got %v, %v", d, ok)
+	}
+	if d, ok := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || d < 59*time.Minute {
+		t.Errorf("future date: got %v, %v", d, ok)
+	}
+	for _, v := range []string{"", "soon", "-1"} {
+		if _, ok := parseRetryAfter(v); ok {
+			t.Errorf("parseRetryAfter(%q) accepted", v)
+		}
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	srv, calls := failingServer(t, 10, http.StatusServiceUnavailable, http.Header{"Retry-After": {"30"}})
+	c := NewClient(WithRetryPolicy(fastRetry))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.Get(ctx, srv.URL+"/quotes/", nil, false)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("returned after %v, want promptly on cancel", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 1; attempt <= 10; attempt++ {
+		want := p.BaseDelay << (attempt - 1)
+		if want > p.MaxDelay || want <= 0 {
+			want = p.MaxDelay
+		}
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(attempt); d < want/2 || d > want {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", attempt, d, want/2, want)
+			}
+		}
+	}
+}
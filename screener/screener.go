@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	robinstock "github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/urls"
 )
 
 // ScreenerRequest represents a request to the Robinhood screener API.
@@ -61,8 +62,7 @@ func Scan(ctx context.Context, client *robinstock.Client, request ScreenerReques
 		return nil, robinstock.ErrNotAuthenticated
 	}
 
-	url := "https://bonfire.robinhood.com/screeners/scan/"
-	resp, err := client.Post(ctx, url, request, true)
+	resp, err := client.Post(ctx, urls.ScreenerScanURL(), request, true)
 	if err != nil {
 		return nil, fmt.Errorf("screener scan failed: %w", err)
 	}
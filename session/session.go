@@ -0,0 +1,284 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ikeboy003/robinstock-go"
+	"github.com/ikeboy003/robinstock-go/auth"
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// ErrUnknownUser is returned for a user ID that was never registered.
+var ErrUnknownUser = errors.New("unknown user")
+
+// Credentials are what a session needs to log in. LoginOptions apply to
+// this user only, e.g. auth.WithTOTPSecret. Token storage is set on the
+// Manager with WithTokenStore; auth.WithTokenStore here has no effect.
+type Credentials struct {
+	Username     string
+	Password     string
+	LoginOptions []auth.LoginOption
+}
+
+// Health is a snapshot of one session's state.
+type Health struct {
+	UserID        string
+	Authenticated bool
+	// ExpiresAt is when the access token expires, or zero if unknown.
+	ExpiresAt   time.Time
+	LastLogin   time.Time
+	LastRefresh time.Time
+	// LastError is the most recent login or request failure, cleared by
+	// the next successful login or refresh.
+	LastError   error
+	LastErrorAt time.Time
+}
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithClientOptions sets the options every session's Client is created
+// with, e.g. a shared logger or rate limit.
+func WithClientOptions(opts ...robinstock_go.Option) Option {
+	return func(m *Manager) {
+		m.clientOpts = append(m.clientOpts, opts...)
+	}
+}
+
+// WithLoginOptions sets login options applied to every session before the
+// user's own Credentials.LoginOptions.
+func WithLoginOptions(opts ...auth.LoginOption) Option {
+	return func(m *Manager) {
+		m.loginOpts = append(m.loginOpts, opts...)
+	}
+}
+
+// WithTokenStore sets where every session's tokens are loaded from and
+// saved to, including tokens obtained by automatic refresh. Tokens are keyed
+// by username. The default is an in-memory store.
+func WithTokenStore(store auth.TokenStore) Option {
+	return func(m *Manager) {
+		m.store = store
+	}
+}
+
+// Manager owns one authenticated Client per user. Sessions log in lazily on
+// first use and again whenever their token can no longer be refreshed. A
+// Manager is safe for concurrent use; logins for different users run in
+// parallel.
+type Manager struct {
+	clientOpts []robinstock_go.Option
+	loginOpts  []auth.LoginOption
+	store      auth.TokenStore
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+type session struct {
+	userID string
+	creds  Credentials
+	client *robinstock_go.Client
+
+	// loginMu serializes logins for this user.
+	loginMu sync.Mutex
+
+	mu          sync.Mutex
+	lastLogin   time.Time
+	lastRefresh time.Time
+	lastErr     error
+	lastErrAt   time.Time
+}
+
+// NewManager returns an empty Manager.
+func NewManager(opts ...Option) *Manager {
+	m := &Manager{sessions: make(map[string]*session)}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.store == nil {
+		m.store = auth.NewMemoryStore()
+	}
+	return m
+}
+
+// Register adds or replaces the credentials for userID. No request is made
+// until the session is first used.
+func (m *Manager) Register(userID string, creds Credentials) {
+	s := &session{userID: userID, creds: creds}
+
+	opts := append([]robinstock_go.Option{}, m.clientOpts...)
+	opts = append(opts,
+		robinstock_go.WithTokenRefreshHandler(func(ctx context.Context, a *models.Auth) {
+			m.refreshed(ctx, s, a)
+		}),
+		robinstock_go.WithMiddleware(s.recordErrors),
+	)
+	s.client = robinstock_go.NewClient(opts...)
+
+	m.mu.Lock()
+	m.sessions[userID] = s
+	m.mu.Unlock()
+}
+
+// Client returns userID's Client, logging in first if the session has no
+// usable token.
+func (m *Manager) Client(ctx context.Context, userID string) (*robinstock_go.Client, error) {
+	s, err := m.session(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ensureLoggedIn(ctx, s); err != nil {
+		return nil, err
+	}
+	return s.client, nil
+}
+
+// Logout logs userID out and deletes its stored token. The session stays
+// registered and logs in again on next use.
+func (m *Manager) Logout(userID string) error {
+	s, err := m.session(userID)
+	if err != nil {
+		return err
+	}
+
+	s.loginMu.Lock()
+	defer s.loginMu.Unlock()
+	auth.Logout(s.creds.Username, s.client, m.loginOptions(s)...)
+	return nil
+}
+
+// Remove logs userID out and forgets the session.
+func (m *Manager) Remove(userID string) {
+	if err := m.Logout(userID); err != nil {
+		return
+	}
+	m.mu.Lock()
+	delete(m.sessions, userID)
+	m.mu.Unlock()
+}
+
+// Health returns the state of userID's session.
+func (m *Manager) Health(userID string) (Health, error) {
+	s, err := m.session(userID)
+	if err != nil {
+		return Health{}, err
+	}
+	return s.health(), nil
+}
+
+// HealthAll returns the state of every session, ordered by user ID.
+func (m *Manager) HealthAll() []Health {
+	m.mu.Lock()
+	sessions := make([]*session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.Unlock()
+
+	health := make([]Health, 0, len(sessions))
+	for _, s := range sessions {
+		health = append(health, s.health())
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].UserID < health[j].UserID })
+	return health
+}
+
+func (m *Manager) session(userID string) (*session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[userID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownUser, userID)
+	}
+	return s, nil
+}
+
+func (m *Manager) loginOptions(s *session) []auth.LoginOption {
+	opts := append([]auth.LoginOption{}, m.loginOpts...)
+	opts = append(opts, s.creds.LoginOptions...)
+	// The Manager's store comes last so login and refresh always agree on
+	// where tokens live.
+	return append(opts, auth.WithTokenStore(m.store))
+}
+
+// ensureLoggedIn logs s in unless it holds a token that is unexpired or can
+// be refreshed.
+func (m *Manager) ensureLoggedIn(ctx context.Context, s *session) error {
+	s.loginMu.Lock()
+	defer s.loginMu.Unlock()
+
+	current := s.client.GetAuth()
+	if current != nil && current.AccessToken != "" {
+		if !current.IsExpired() {
+			return nil
+		}
+		if current.RefreshToken != "" {
+			if _, err := s.client.RefreshAuth(ctx); err == nil {
+				return nil
+			}
+		}
+	}
+
+	s.client.Logger().InfoContext(ctx, "logging in session", "user_id", s.userID)
+	_, err := auth.Login(ctx, s.client, s.creds.Username, s.creds.Password, "", m.loginOptions(s)...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.lastErr = err
+		s.lastErrAt = time.Now()
+		return fmt.Errorf("login %s: %w", s.userID, err)
+	}
+	s.lastLogin = time.Now()
+	s.lastErr = nil
+	return nil
+}
+
+// refreshed records a token refresh and persists the new token.
+func (m *Manager) refreshed(ctx context.Context, s *session, a *models.Auth) {
+	s.mu.Lock()
+	s.lastRefresh = time.Now()
+	s.lastErr = nil
+	s.mu.Unlock()
+
+	if err := m.store.Save(ctx, s.creds.Username, a); err != nil {
+		s.client.Logger().WarnContext(ctx, "save refreshed token failed", "user_id", s.userID, "error", err)
+	}
+}
+
+// recordErrors is middleware that keeps the session's last request error.
+func (s *session) recordErrors(next robinstock_go.Handler) robinstock_go.Handler {
+	return func(ctx context.Context, req *robinstock_go.Request) (*models.Response, error) {
+		resp, err := next(ctx, req)
+		if err != nil && ctx.Err() == nil {
+			s.mu.Lock()
+			s.lastErr = err
+			s.lastErrAt = time.Now()
+			s.mu.Unlock()
+		}
+		return resp, err
+	}
+}
+
+func (s *session) health() Health {
+	h := Health{UserID: s.userID}
+	if a := s.client.GetAuth(); a != nil && a.AccessToken != "" {
+		h.Authenticated = !a.IsExpired()
+		h.ExpiresAt = a.ExpiresAt()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h.LastLogin = s.lastLogin
+	h.LastRefresh = s.lastRefresh
+	h.LastError = s.lastErr
+	h.LastErrorAt = s.lastErrAt
+	return h
+}
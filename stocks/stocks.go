@@ -6,41 +6,30 @@ import (
 
 	"github.com/ikeboy003/robinstock-go"
 	"github.com/ikeboy003/robinstock-go/models"
-	"github.com/ikeboy003/robinstock-go/utils"
+	"github.com/ikeboy003/robinstock-go/urls"
 )
 
+// symbolHistoricals is one entry of the historicals endpoint's results.
+type symbolHistoricals struct {
+	Symbol      string                  `json:"symbol"`
+	Historicals []models.HistoricalData `json:"historicals"`
+}
+
 // GetInstrumentBySymbol returns instrument data for a symbol.
 func GetInstrumentBySymbol(ctx context.Context, client *robinstock_go.Client, symbol string) (*models.Instrument, error) {
 	symbol = robinstock_go.NormalizeSymbol(symbol)
 
 	params := map[string]string{"symbol": symbol}
-	resp, err := client.Get(ctx, models.BaseURL+"/instruments/", params, false)
+	page, err := robinstock_go.GetJSON[robinstock_go.Page[models.Instrument]](ctx, client, urls.InstrumentsURL(), params, false)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(resp.Results) == 0 {
+	if len(page.Results) == 0 {
 		return nil, fmt.Errorf("instrument not found for symbol: %s", symbol)
 	}
 
-	result := resp.Results[0]
-	instrument := &models.Instrument{
-		ID:           utils.GetString(result, "id"),
-		URL:          utils.GetString(result, "url"),
-		Symbol:       utils.GetString(result, "symbol"),
-		Name:         utils.GetString(result, "name"),
-		SimpleName:   utils.GetString(result, "simple_name"),
-		ListDate:     utils.GetString(result, "list_date"),
-		Country:      utils.GetString(result, "country"),
-		Type:         utils.GetString(result, "type"),
-		Tradeable:    utils.GetBool(result, "tradeable"),
-		State:        utils.GetString(result, "state"),
-		Fundamentals: utils.GetString(result, "fundamentals"),
-		Quote:        utils.GetString(result, "quote"),
-		Market:       utils.GetString(result, "market"),
-	}
-
-	return instrument, nil
+	return &page.Results[0], nil
 }
 
 // GetInstrumentsBySymbols returns instrument data for multiple symbols.
@@ -49,29 +38,16 @@ func GetInstrumentsBySymbols(ctx context.Context, client *robinstock_go.Client,
 	symbolsParam := robinstock_go.JoinSymbols(symbols)
 
 	params := map[string]string{"symbols": symbolsParam}
-	resp, err := client.Get(ctx, models.BaseURL+"/instruments/", params, false)
+	page, err := robinstock_go.GetJSON[robinstock_go.Page[*models.Instrument]](ctx, client, urls.InstrumentsURL(), params, false)
 	if err != nil {
 		return nil, err
 	}
 
 	var instruments []models.Instrument
-	for _, result := range resp.Results {
-		instrument := models.Instrument{
-			ID:           utils.GetString(result, "id"),
-			URL:          utils.GetString(result, "url"),
-			Symbol:       utils.GetString(result, "symbol"),
-			Name:         utils.GetString(result, "name"),
-			SimpleName:   utils.GetString(result, "simple_name"),
-			ListDate:     utils.GetString(result, "list_date"),
-			Country:      utils.GetString(result, "country"),
-			Type:         utils.GetString(result, "type"),
-			Tradeable:    utils.GetBool(result, "tradeable"),
-			State:        utils.GetString(result, "state"),
-			Fundamentals: utils.GetString(result, "fundamentals"),
-			Quote:        utils.GetString(result, "quote"),
-			Market:       utils.GetString(result, "market"),
+	for _, instrument := range page.Results {
+		if instrument != nil {
+			instruments = append(instruments, *instrument)
 		}
-		instruments = append(instruments, instrument)
 	}
 
 	return instruments, nil
@@ -97,29 +73,16 @@ func GetQuotes(ctx context.Context, client *robinstock_go.Client, symbols ...str
 	symbolsParam := robinstock_go.JoinSymbols(symbols)
 
 	params := map[string]string{"symbols": symbolsParam}
-	resp, err := client.Get(ctx, models.BaseURL+"/quotes/", params, false)
+	page, err := robinstock_go.GetJSON[robinstock_go.Page[*models.Quote]](ctx, client, urls.QuotesURL(), params, false)
 	if err != nil {
 		return nil, err
 	}
 
 	var quotes []models.Quote
-	for _, result := range resp.Results {
-		quote := models.Quote{
-			Symbol:                      utils.GetString(result, "symbol"),
-			InstrumentID:                utils.GetString(result, "instrument_id"),
-			AskPrice:                    utils.GetString(result, "ask_price"),
-			AskSize:                     utils.GetInt(result, "ask_size"),
-			BidPrice:                    utils.GetString(result, "bid_price"),
-			BidSize:                     utils.GetInt(result, "bid_size"),
-			LastTradePrice:              utils.GetString(result, "last_trade_price"),
-			LastExtendedHoursTradePrice: utils.GetString(result, "last_extended_hours_trade_price"),
-			PreviousClose:               utils.GetString(result, "previous_close"),
-			AdjustedPreviousClose:       utils.GetString(result, "adjusted_previous_close"),
-			TradingHalted:               utils.GetBool(result, "trading_halted"),
-			HasTraded:                   utils.GetBool(result, "has_traded"),
-			UpdatedAt:                   utils.GetString(result, "updated_at"),
+	for _, quote := range page.Results {
+		if quote != nil {
+			quotes = append(quotes, *quote)
 		}
-		quotes = append(quotes, quote)
 	}
 
 	return quotes, nil
@@ -129,38 +92,16 @@ func GetQuotes(ctx context.Context, client *robinstock_go.Client, symbols ...str
 func GetFundamentals(ctx context.Context, client *robinstock_go.Client, symbol string) (*models.Fundamental, error) {
 	symbol = robinstock_go.NormalizeSymbol(symbol)
 
-	url := fmt.Sprintf("%s/fundamentals/%s/", models.BaseURL, symbol)
-	resp, err := client.Get(ctx, url, nil, false)
-	if err != nil {
-		return nil, err
-	}
-
-	fundamental := &models.Fundamental{
-		Open:                utils.GetString(resp.Data, "open"),
-		High:                utils.GetString(resp.Data, "high"),
-		Low:                 utils.GetString(resp.Data, "low"),
-		Volume:              utils.GetString(resp.Data, "volume"),
-		AverageVolume:       utils.GetString(resp.Data, "average_volume"),
-		AverageVolume2Weeks: utils.GetString(resp.Data, "average_volume_2_weeks"),
-		High52Weeks:         utils.GetString(resp.Data, "high_52_weeks"),
-		Low52Weeks:          utils.GetString(resp.Data, "low_52_weeks"),
-		MarketCap:           utils.GetString(resp.Data, "market_cap"),
-		DividendYield:       utils.GetString(resp.Data, "dividend_yield"),
-		PERatio:             utils.GetString(resp.Data, "pe_ratio"),
-		Description:         utils.GetString(resp.Data, "description"),
-		Instrument:          utils.GetString(resp.Data, "instrument"),
-	}
-
-	return fundamental, nil
+	return robinstock_go.GetJSON[models.Fundamental](ctx, client, urls.FundamentalsURL(symbol), nil, false)
 }
 
 // GetSymbolByURL returns the stock symbol for a given instrument URL.
 func GetSymbolByURL(ctx context.Context, client *robinstock_go.Client, url string) (string, error) {
-	resp, err := client.Get(ctx, url, nil, false)
+	instrument, err := robinstock_go.GetJSON[models.Instrument](ctx, client, url, nil, false)
 	if err != nil {
 		return "", err
 	}
-	return utils.GetString(resp.Data, "symbol"), nil
+	return instrument.Symbol, nil
 }
 
 // GetHistoricals returns historical price data for a symbol.
@@ -173,44 +114,16 @@ func GetHistoricals(ctx context.Context, client *robinstock_go.Client, symbol st
 		"span":     span,
 	}
 
-	url := models.BaseURL + "/quotes/historicals/"
-	resp, err := client.Get(ctx, url, params, false)
+	page, err := robinstock_go.GetJSON[robinstock_go.Page[symbolHistoricals]](ctx, client, urls.HistoricalsURL(), params, false)
 	if err != nil {
 		return nil, err
 	}
 
-	resultsArray, ok := resp.Data["results"].([]interface{})
-	if !ok || len(resultsArray) == 0 {
+	if len(page.Results) == 0 {
 		return nil, fmt.Errorf("no historical data found")
 	}
 
-	firstResult, ok := resultsArray[0].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid historical data format")
-	}
-
-	historicalsArray, ok := firstResult["historicals"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("no historicals array found")
-	}
-
-	var historicals []models.HistoricalData
-	for _, item := range historicalsArray {
-		if hist, ok := item.(map[string]interface{}); ok {
-			historical := models.HistoricalData{
-				OpenPrice:    utils.GetString(hist, "open_price"),
-				ClosePrice:   utils.GetString(hist, "close_price"),
-				HighPrice:    utils.GetString(hist, "high_price"),
-				LowPrice:     utils.GetString(hist, "low_price"),
-				Volume:       utils.GetInt(hist, "volume"),
-				Session:      utils.GetString(hist, "session"),
-				Interpolated: utils.GetBool(hist, "interpolated"),
-			}
-			historicals = append(historicals, historical)
-		}
-	}
-
-	return historicals, nil
+	return page.Results[0].Historicals, nil
 }
 
 // GetRatings returns analyst ratings for a stock.
@@ -222,7 +135,7 @@ func GetRatings(ctx context.Context, client *robinstock_go.Client, symbol string
 		return nil, err
 	}
 
-	url := models.BaseURL + "/midlands/ratings/" + instrument.ID + "/"
+	url := urls.RatingsURL(instrument.ID)
 	resp, err := client.Get(ctx, url, nil, false)
 	if err != nil {
 		return nil, err
@@ -235,7 +148,7 @@ func GetRatings(ctx context.Context, client *robinstock_go.Client, symbol string
 func GetNews(ctx context.Context, client *robinstock_go.Client, symbol string) ([]map[string]interface{}, error) {
 	symbol = robinstock_go.NormalizeSymbol(symbol)
 
-	url := models.BaseURL + "/midlands/news/" + symbol + "/"
+	url := urls.NewsURL(symbol)
 	resp, err := client.Get(ctx, url, nil, false)
 	if err != nil {
 		return nil, err
@@ -262,7 +175,7 @@ func GetPopularity(ctx context.Context, client *robinstock_go.Client, symbol str
 		return nil, err
 	}
 
-	url := models.BaseURL + "/instruments/" + instrument.ID + "/popularity/"
+	url := urls.PopularityURL(instrument.ID)
 	resp, err := client.Get(ctx, url, nil, false)
 	if err != nil {
 		return nil, err
@@ -280,7 +193,7 @@ func GetSplits(ctx context.Context, client *robinstock_go.Client, symbol string)
 		return nil, err
 	}
 
-	url := models.BaseURL + "/instruments/" + instrument.ID + "/splits/"
+	url := urls.SplitsURL(instrument.ID)
 	results, err := client.FetchAllPages(ctx, url, false)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,66 @@
+package robinstock_go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ikeboy003/robinstock-go/models"
+)
+
+// Page is one page of a paginated Robinhood list response.
+type Page[T any] struct {
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+	Results  []T    `json:"results"`
+}
+
+// DecodeResponse unmarshals the body of resp into a T.
+func DecodeResponse[T any](resp *models.Response) (*T, error) {
+	var v T
+	if len(resp.Body) == 0 {
+		return &v, nil
+	}
+	if err := json.Unmarshal(resp.Body, &v); err != nil {
+		return nil, fmt.Errorf("decode %T: %w", v, err)
+	}
+	return &v, nil
+}
+
+// GetJSON executes a GET request and unmarshals the response body into a T.
+func GetJSON[T any](ctx context.Context, c *Client, urlStr string, params map[string]string, authenticated bool) (*T, error) {
+	resp, err := c.Get(ctx, urlStr, params, authenticated)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeResponse[T](resp)
+}
+
+// PostJSON executes a POST request and unmarshals the response body into a T.
+func PostJSON[T any](ctx context.Context, c *Client, urlStr string, body interface{}, authenticated bool) (*T, error) {
+	resp, err := c.Post(ctx, urlStr, body, authenticated)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeResponse[T](resp)
+}
+
+// FetchAllPagesTyped fetches all pages of a paginated response and
+// unmarshals every result into a T.
+func FetchAllPagesTyped[T any](ctx context.Context, c *Client, initialURL string, authenticated bool) ([]T, error) {
+	var all []T
+
+	p := c.Pages(initialURL, authenticated)
+	for p.Next(ctx) {
+		results, err := PageResults[T](p)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, results...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
@@ -11,118 +11,118 @@ func LoginURL() string {
 }
 
 func ChallengeURL(challengeID string) string {
-	return fmt.Sprintf("https://api.robinhood.com/challenge/%s/respond/", challengeID)
+	return fmt.Sprintf("%s/challenge/%s/respond/", models.BaseURL, challengeID)
 }
 
 func PathfinderUserMachineURL() string {
-	return "https://api.robinhood.com/pathfinder/user_machine/"
+	return models.BaseURL + "/pathfinder/user_machine/"
 }
 
 func SheriffInquiryURL(machineID string) string {
-	return fmt.Sprintf("https://api.robinhood.com/pathfinder/inquiries/%s/user_view/", machineID)
+	return fmt.Sprintf("%s/pathfinder/inquiries/%s/user_view/", models.BaseURL, machineID)
 }
 
 func SheriffChallengeStatusURL(challengeID string) string {
-	return fmt.Sprintf("https://api.robinhood.com/push/%s/get_prompts_status/", challengeID)
+	return fmt.Sprintf("%s/push/%s/get_prompts_status/", models.BaseURL, challengeID)
 }
 
 func PhoenixURL() string {
-	return "https://phoenix.robinhood.com/accounts/unified"
+	return models.PhoenixBaseURL + "/accounts/unified"
 }
 
 func AccountsURL() string {
-	return "https://api.robinhood.com/accounts/"
+	return models.BaseURL + "/accounts/"
 }
 
 func AccountURL(accountNumber string) string {
-	return fmt.Sprintf("https://api.robinhood.com/accounts/%s/", accountNumber)
+	return fmt.Sprintf("%s/accounts/%s/", models.BaseURL, accountNumber)
 }
 
 func PositionsURL() string {
-	return "https://api.robinhood.com/positions/"
+	return models.BaseURL + "/positions/"
 }
 
 func PortfoliosURL() string {
-	return "https://api.robinhood.com/portfolios/"
+	return models.BaseURL + "/portfolios/"
 }
 
 func PortfolioURL(accountNumber string) string {
-	return fmt.Sprintf("https://api.robinhood.com/portfolios/%s/", accountNumber)
+	return fmt.Sprintf("%s/portfolios/%s/", models.BaseURL, accountNumber)
 }
 
 func DividendsURL() string {
-	return "https://api.robinhood.com/dividends/"
+	return models.BaseURL + "/dividends/"
 }
 
 func NotificationsURL(tracker bool) string {
 	if tracker {
-		return "https://api.robinhood.com/midlands/notifications/notification_tracker/"
+		return models.BaseURL + "/midlands/notifications/notification_tracker/"
 	}
-	return "https://api.robinhood.com/notifications/devices/"
+	return models.BaseURL + "/notifications/devices/"
 }
 
 func BankTransfersURL() string {
-	return "https://api.robinhood.com/ach/transfers/"
+	return models.BaseURL + "/ach/transfers/"
 }
 
 func LinkedURL() string {
-	return "https://api.robinhood.com/ach/relationships/"
+	return models.BaseURL + "/ach/relationships/"
 }
 
 func InstrumentsURL() string {
-	return "https://api.robinhood.com/instruments/"
+	return models.BaseURL + "/instruments/"
 }
 
 func QuotesURL() string {
-	return "https://api.robinhood.com/quotes/"
+	return models.BaseURL + "/quotes/"
 }
 
 func FundamentalsURL(symbol string) string {
-	return fmt.Sprintf("https://api.robinhood.com/fundamentals/%s/", symbol)
+	return fmt.Sprintf("%s/fundamentals/%s/", models.BaseURL, symbol)
 }
 
 func HistoricalsURL() string {
-	return "https://api.robinhood.com/quotes/historicals/"
+	return models.BaseURL + "/quotes/historicals/"
 }
 
 func BasicProfileURL() string {
-	return "https://api.robinhood.com/user/basic_info/"
+	return models.BaseURL + "/user/basic_info/"
 }
 
 func InvestmentProfileURL() string {
-	return "https://api.robinhood.com/user/investment_profile/"
+	return models.BaseURL + "/user/investment_profile/"
 }
 
 func SecurityProfileURL() string {
-	return "https://api.robinhood.com/user/additional_info/"
+	return models.BaseURL + "/user/additional_info/"
 }
 
 func UserProfileURL() string {
-	return "https://api.robinhood.com/user/"
+	return models.BaseURL + "/user/"
 }
 
 func MarketsURL() string {
-	return "https://api.robinhood.com/markets/"
+	return models.BaseURL + "/markets/"
 }
 
 func MarketHoursURL(market, date string) string {
-	return fmt.Sprintf("https://api.robinhood.com/markets/%s/hours/%s/", market, date)
+	return fmt.Sprintf("%s/markets/%s/hours/%s/", models.BaseURL, market, date)
 }
 
 func MoversSP500URL() string {
-	return "https://api.robinhood.com/midlands/movers/sp500/"
+	return models.BaseURL + "/midlands/movers/sp500/"
 }
 
 func Top100MostPopularURL() string {
-	return "https://api.robinhood.com/midlands/tags/tag/100-most-popular/"
+	return models.BaseURL + "/midlands/tags/tag/100-most-popular/"
 }
 
 func MarketCategoryURL(category string) string {
-	return fmt.Sprintf("https://api.robinhood.com/midlands/tags/tag/%s/", category)
+	return fmt.Sprintf("%s/midlands/tags/tag/%s/", models.BaseURL, category)
 }
 
 func OrdersURL(orderID, accountNumber, startDate *string) string {
-	url := "https://api.robinhood.com/orders/"
+	url := models.BaseURL + "/orders/"
 	if orderID != nil {
 		url += *orderID + "/"
 	}
@@ -143,11 +143,11 @@ func OrdersURL(orderID, accountNumber, startDate *string) string {
 }
 
 func CancelURL(orderID string) string {
-	return fmt.Sprintf("https://api.robinhood.com/orders/%s/cancel/", orderID)
+	return fmt.Sprintf("%s/orders/%s/cancel/", models.BaseURL, orderID)
 }
 
 func OptionOrdersURL(orderID, accountNumber, startDate *string) string {
-	url := "https://api.robinhood.com/options/orders/"
+	url := models.BaseURL + "/options/orders/"
 	if orderID != nil {
 		url += *orderID + "/"
 	}
@@ -168,5 +168,57 @@ func OptionOrdersURL(orderID, accountNumber, startDate *string) string {
 }
 
 func OptionCancelURL(id string) string {
-	return fmt.Sprintf("https://api.robinhood.com/options/orders/%s/cancel/", id)
+	return fmt.Sprintf("%s/options/orders/%s/cancel/", models.BaseURL, id)
+}
+
+func AllAccountsURL() string {
+	return models.BaseURL + "/accounts/?default_to_all_accounts=true"
+}
+
+func PortfolioHistoricalsURL(accountNumber string) string {
+	return fmt.Sprintf("%s/portfolios/historicals/%s/", models.BaseURL, accountNumber)
+}
+
+func RatingsURL(instrumentID string) string {
+	return fmt.Sprintf("%s/midlands/ratings/%s/", models.BaseURL, instrumentID)
+}
+
+func NewsURL(symbol string) string {
+	return fmt.Sprintf("%s/midlands/news/%s/", models.BaseURL, symbol)
+}
+
+func PopularityURL(instrumentID string) string {
+	return fmt.Sprintf("%s/instruments/%s/popularity/", models.BaseURL, instrumentID)
+}
+
+func SplitsURL(instrumentID string) string {
+	return fmt.Sprintf("%s/instruments/%s/splits/", models.BaseURL, instrumentID)
+}
+
+func EarningsURL() string {
+	return models.BaseURL + "/marketdata/earnings/"
+}
+
+func OptionEventsURL() string {
+	return models.BaseURL + "/options/events/"
+}
+
+func OptionChainsURL() string {
+	return models.BaseURL + "/options/chains/"
+}
+
+func OptionInstrumentsURL() string {
+	return models.BaseURL + "/options/instruments/"
+}
+
+func OptionInstrumentURL(optionID string) string {
+	return fmt.Sprintf("%s/options/instruments/%s/", models.BaseURL, optionID)
+}
+
+func OptionPositionsURL() string {
+	return models.BaseURL + "/options/positions/"
+}
+
+func ScreenerScanURL() string {
+	return models.BonfireBaseURL + "/screeners/scan/"
 }